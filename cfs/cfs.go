@@ -0,0 +1,176 @@
+// Package cfs implements a scheduler modelled on the Linux Completely Fair
+// Scheduler. Each job accumulates virtual runtime at a rate inversely
+// proportional to the weight of its nice value, and the runnable job with
+// the lowest virtual runtime is run next. A job's timeslice is its weighted
+// share of the target latency, but never less than the minimum granularity.
+package cfs
+
+import (
+	"container/heap"
+	"dat320/lab4/scheduler"
+	"dat320/lab4/scheduler/cpu"
+	"dat320/lab4/scheduler/job"
+	"dat320/lab4/scheduler/system/systime"
+	"errors"
+	"time"
+)
+
+var errInvalidConfig = errors.New("invalid argument: target latency and minimum granularity must be greater than 0")
+
+// Config holds the parameters of the CFS scheduler.
+type Config struct {
+	TargetLatency  time.Duration // period in which every runnable job should run once
+	MinGranularity time.Duration // lower bound on a job's timeslice
+}
+
+// DefaultConfig returns the Linux defaults for a multi-core machine.
+func DefaultConfig() Config {
+	return Config{
+		TargetLatency:  24 * time.Millisecond,
+		MinGranularity: 3 * time.Millisecond,
+	}
+}
+
+func init() {
+	scheduler.Register("cfs", func(cpus []*cpu.CPU, opts scheduler.Options) (scheduler.Scheduler, error) {
+		config := DefaultConfig()
+		if opts.Quantum > 0 {
+			config.MinGranularity = opts.Quantum
+		}
+		return New(cpus, config)
+	})
+}
+
+var _ scheduler.Scheduler = (*cfs)(nil)
+
+type cfs struct {
+	cpus        []*cpu.CPU
+	queue       runQueue
+	config      Config
+	ran         []time.Duration // time the current job has run on each CPU since dispatch
+	minVRuntime time.Duration   // monotonically increasing lower bound on virtual runtimes
+}
+
+// New returns a CFS scheduler for the given CPUs. It returns an error if
+// a CPU's context switch cost is not less than the minimum granularity,
+// which is the timeslice of every job once enough jobs are runnable.
+func New(cpus []*cpu.CPU, config Config) (*cfs, error) {
+	if len(cpus) == 0 {
+		panic("cfs scheduler requires at least one CPU")
+	}
+	if config.TargetLatency <= 0 || config.MinGranularity <= 0 {
+		return nil, errInvalidConfig
+	}
+	if err := scheduler.CheckSwitchCost(cpus, config.MinGranularity); err != nil {
+		return nil, err
+	}
+	return &cfs{
+		cpus:   cpus,
+		config: config,
+		ran:    make([]time.Duration, len(cpus)),
+	}, nil
+}
+
+// Add adds the job to the run queue. A new job starts at the queue's minimum
+// virtual runtime, so that it cannot monopolize the CPUs to catch up.
+func (s *cfs) Add(job *job.Job) {
+	if job.VRuntime < s.minVRuntime {
+		job.VRuntime = s.minVRuntime
+	}
+	heap.Push(&s.queue, job)
+}
+
+// Tick runs the scheduled jobs for the system time, and returns
+// the number of jobs finished in this tick. Depending on scheduler requirements,
+// the Tick method may assign new jobs to the CPU before returning.
+func (s *cfs) Tick(systemTime time.Duration) int {
+	jobsFinished := 0
+	for i, c := range s.cpus {
+		if !c.IsRunning() {
+			continue
+		}
+		current := c.CurrentJob()
+		current.VRuntime += systime.TickDuration * nice0Weight / time.Duration(Weight(current.Nice))
+		s.ran[i] += systime.TickDuration
+		if c.Tick() {
+			jobsFinished++
+		}
+	}
+	s.updateMinVRuntime()
+	// timeslices are computed before any job is put back, so that all
+	// jobs expiring in this tick see the same number of runnable jobs
+	expired := make([]bool, len(s.cpus))
+	for i, c := range s.cpus {
+		expired[i] = c.IsRunning() && s.ran[i] >= s.timeslice(c.CurrentJob())
+	}
+	for i, c := range s.cpus {
+		if expired[i] {
+			heap.Push(&s.queue, c.CurrentJob())
+		}
+	}
+	for i, c := range s.cpus {
+		if expired[i] || !c.IsRunning() {
+			s.reassign(i)
+		}
+	}
+	return jobsFinished
+}
+
+// reassign assigns the job with the lowest virtual runtime to CPU i.
+func (s *cfs) reassign(i int) {
+	var nxtJob *job.Job
+	if s.queue.Len() > 0 {
+		nxtJob = heap.Pop(&s.queue).(*job.Job)
+	}
+	s.ran[i] = 0
+	s.cpus[i].Assign(nxtJob)
+}
+
+// timeslice returns the job's share of the scheduling period, which is the
+// target latency stretched so that every runnable job gets at least the
+// minimum granularity.
+func (s *cfs) timeslice(j *job.Job) time.Duration {
+	running := s.Running()
+	nrRunning := s.queue.Len() + len(running)
+	period := s.config.TargetLatency
+	if minPeriod := time.Duration(nrRunning) * s.config.MinGranularity; minPeriod > period {
+		period = minPeriod
+	}
+	totalWeight := 0
+	for _, rj := range append(running, s.queue.jobs...) {
+		totalWeight += Weight(rj.Nice)
+	}
+	slice := period * time.Duration(Weight(j.Nice)) / time.Duration(totalWeight)
+	if slice < s.config.MinGranularity {
+		slice = s.config.MinGranularity
+	}
+	return slice
+}
+
+// updateMinVRuntime advances the minimum virtual runtime to the lowest
+// virtual runtime among the runnable jobs, if that is larger.
+func (s *cfs) updateMinVRuntime() {
+	lowest := time.Duration(-1)
+	candidates := s.Running()
+	if min := s.queue.min(); min != nil {
+		candidates = append(candidates, min)
+	}
+	for _, j := range candidates {
+		if lowest < 0 || j.VRuntime < lowest {
+			lowest = j.VRuntime
+		}
+	}
+	if lowest > s.minVRuntime {
+		s.minVRuntime = lowest
+	}
+}
+
+// Len returns the number of jobs waiting in the run queue.
+func (s *cfs) Len() int {
+	return s.queue.Len()
+}
+
+// Running returns the jobs currently running on the CPUs.
+func (s *cfs) Running() job.Jobs {
+	return cpu.Running(s.cpus)
+}
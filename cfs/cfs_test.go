@@ -0,0 +1,80 @@
+package cfs
+
+import (
+	"dat320/lab4/scheduler/cpu"
+	"dat320/lab4/scheduler/job"
+	"dat320/lab4/scheduler/system/systime"
+	"testing"
+	"time"
+)
+
+const ms = time.Millisecond
+
+func TestWeight(t *testing.T) {
+	tests := []struct{ nice, want int }{
+		{-25, 88761}, {-20, 88761}, {0, 1024}, {1, 820}, {19, 15}, {25, 15},
+	}
+	for _, test := range tests {
+		if got := Weight(test.nice); got != test.want {
+			t.Errorf("Weight(%d) = %d, want %d", test.nice, got, test.want)
+		}
+	}
+}
+
+func TestCFSShare(t *testing.T) {
+	clk := &systime.ManualClock{}
+	s, err := New(cpu.NewCPUs(1), DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// weights 1024 and 335 give shares of 75% and 25%
+	jobs := job.Jobs{job.New(0, time.Second), job.New(0, time.Second)}
+	jobs[1].Nice = 5
+	for _, j := range jobs {
+		j.Scheduled(clk)
+		s.Add(j)
+	}
+	ticks := make([]int, len(jobs))
+	for ; clk.Time < 1000*ms; clk.Time += ms {
+		for _, running := range s.Running() {
+			for i, j := range jobs {
+				if running == j {
+					ticks[i]++
+				}
+			}
+		}
+		s.Tick(clk.Time)
+	}
+	if ticks[0] < 730 || ticks[0] > 770 {
+		t.Errorf("nice 0 job ran %d of 1000 ticks, want about 753", ticks[0])
+	}
+
+	// a new job starts at the minimum virtual runtime instead of zero
+	late := job.New(0, time.Second)
+	late.Scheduled(clk)
+	s.Add(late)
+	if late.VRuntime == 0 || late.VRuntime != s.minVRuntime {
+		t.Errorf("late.VRuntime = %v, want minimum virtual runtime %v", late.VRuntime, s.minVRuntime)
+	}
+}
+
+func TestCFSTimeslice(t *testing.T) {
+	s, err := New(cpu.NewCPUs(1), Config{TargetLatency: 20 * ms, MinGranularity: 4 * ms})
+	if err != nil {
+		t.Fatal(err)
+	}
+	jobs := job.Jobs{job.New(0, time.Second), job.New(0, time.Second)}
+	for _, j := range jobs {
+		s.Add(j)
+	}
+	if got, want := s.timeslice(jobs[0]), 10*ms; got != want {
+		t.Errorf("timeslice with 2 jobs = %v, want %v", got, want)
+	}
+	for i := 0; i < 8; i++ {
+		s.Add(job.New(0, time.Second))
+	}
+	// ten jobs stretch the period to 10 * 4ms
+	if got, want := s.timeslice(jobs[0]), 4*ms; got != want {
+		t.Errorf("timeslice with 10 jobs = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,47 @@
+package cfs
+
+import "dat320/lab4/scheduler/job"
+
+// runQueue is a min-heap of jobs ordered by virtual runtime;
+// jobs with equal virtual runtime are ordered by insertion.
+// It implements heap.Interface.
+type runQueue struct {
+	jobs job.Jobs
+	seq  []uint64
+	next uint64
+}
+
+func (rq *runQueue) Len() int { return len(rq.jobs) }
+
+func (rq *runQueue) Less(i, j int) bool {
+	if rq.jobs[i].VRuntime != rq.jobs[j].VRuntime {
+		return rq.jobs[i].VRuntime < rq.jobs[j].VRuntime
+	}
+	return rq.seq[i] < rq.seq[j]
+}
+
+func (rq *runQueue) Swap(i, j int) {
+	rq.jobs[i], rq.jobs[j] = rq.jobs[j], rq.jobs[i]
+	rq.seq[i], rq.seq[j] = rq.seq[j], rq.seq[i]
+}
+
+func (rq *runQueue) Push(x interface{}) {
+	rq.jobs = append(rq.jobs, x.(*job.Job))
+	rq.seq = append(rq.seq, rq.next)
+	rq.next++
+}
+
+func (rq *runQueue) Pop() interface{} {
+	last := len(rq.jobs) - 1
+	j := rq.jobs[last]
+	rq.jobs, rq.seq = rq.jobs[:last], rq.seq[:last]
+	return j
+}
+
+// min returns the job with the lowest virtual runtime without removing it.
+func (rq *runQueue) min() *job.Job {
+	if len(rq.jobs) == 0 {
+		return nil
+	}
+	return rq.jobs[0]
+}
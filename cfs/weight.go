@@ -0,0 +1,33 @@
+package cfs
+
+const (
+	minNice     = -20
+	maxNice     = 19
+	nice0Weight = 1024
+)
+
+// weights maps nice values -20..19 to load weights, as in the Linux kernel's
+// sched_prio_to_weight table. Each step in niceness changes a job's CPU share
+// by about 10% relative to a job at the neighbouring nice value.
+var weights = [...]int{
+	/* -20 */ 88761, 71755, 56483, 46273, 36291,
+	/* -15 */ 29154, 23254, 18705, 14949, 11916,
+	/* -10 */ 9548, 7620, 6100, 4904, 3906,
+	/*  -5 */ 3121, 2501, 1991, 1586, 1277,
+	/*   0 */ 1024, 820, 655, 526, 423,
+	/*   5 */ 335, 272, 215, 172, 137,
+	/*  10 */ 110, 87, 70, 56, 45,
+	/*  15 */ 36, 29, 23, 18, 15,
+}
+
+// Weight returns the load weight of the given nice value;
+// nice values outside [-20, 19] are clamped.
+func Weight(nice int) int {
+	if nice < minNice {
+		nice = minNice
+	}
+	if nice > maxNice {
+		nice = maxNice
+	}
+	return weights[nice-minNice]
+}
@@ -0,0 +1,156 @@
+// Command schedsim runs a workload through one or more scheduling policies
+// and prints their metrics side by side, followed by a Gantt chart for each policy.
+//
+// Usage:
+//
+//	schedsim -workload jobs.csv [-policies fifo,sjf,rr] [-quantum 10ms] [-switch 0] [-cpus 1] [-cache 0]
+//
+// The workload is read with workload.Load; see package workload for the
+// supported formats. Run schedsim -h for the list of policies.
+package main
+
+import (
+	"dat320/lab4/scheduler"
+	"dat320/lab4/scheduler/cpu"
+	"dat320/lab4/scheduler/job"
+	"dat320/lab4/scheduler/metrics"
+	"dat320/lab4/scheduler/system"
+	"dat320/lab4/scheduler/timeline"
+	"dat320/lab4/scheduler/workload"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	_ "dat320/lab4/scheduler/cfs"
+	_ "dat320/lab4/scheduler/fifo"
+	_ "dat320/lab4/scheduler/lottery"
+	_ "dat320/lab4/scheduler/mlfq"
+	_ "dat320/lab4/scheduler/priority"
+	_ "dat320/lab4/scheduler/rr"
+	_ "dat320/lab4/scheduler/rt"
+	_ "dat320/lab4/scheduler/sjf"
+	_ "dat320/lab4/scheduler/steal"
+	_ "dat320/lab4/scheduler/stride"
+)
+
+var (
+	errNoWorkload = errors.New("missing -workload flag")
+	errNoCPUs     = errors.New("-cpus must be at least 1")
+	errEngines    = errors.New("-parallel and -tickless cannot be combined")
+	errSwitchCost = errors.New("-switch must not be negative")
+)
+
+// config holds the command line flags.
+type config struct {
+	workload string
+	policies []string
+	cpus     int
+	gantt    bool
+	parallel bool
+	tickless bool
+	opts     scheduler.Options
+}
+
+func main() {
+	var (
+		cfg      config
+		policies string
+	)
+	flag.StringVar(&cfg.workload, "workload", "", "workload `file`; see package workload for the formats")
+	flag.StringVar(&policies, "policies", "fifo,sjf,rr", "comma-separated `list` of policies to compare")
+	flag.IntVar(&cfg.cpus, "cpus", 1, "number of CPUs")
+	flag.BoolVar(&cfg.gantt, "gantt", true, "print a Gantt chart for each policy")
+	flag.BoolVar(&cfg.parallel, "parallel", false, "run each CPU in its own goroutine")
+	flag.BoolVar(&cfg.tickless, "tickless", false, "jump from event to event instead of simulating every tick")
+	flag.DurationVar(&cfg.opts.Quantum, "quantum", 10*time.Millisecond, "time slice of preemptive policies")
+	flag.DurationVar(&cfg.opts.SwitchCost, "switch", 0, "context switch cost charged to a CPU on every dispatch")
+	flag.IntVar(&cfg.opts.CacheSize, "cache", 0, "cache capacity of each CPU, in job sizes; zero disables caches")
+	flag.DurationVar(&cfg.opts.CacheWarmup, "warmup", 5*time.Millisecond, "time for a job's working set to become warm in a cache")
+	flag.IntVar(&cfg.opts.WarmSpeed, "warmspeed", 2, "speed of jobs whose working set is warm")
+	flag.IntVar(&cfg.opts.Levels, "levels", 0, "number of priority levels (mlfq); zero for the default")
+	flag.DurationVar(&cfg.opts.BoostPeriod, "boost", 0, "priority boost period (mlfq); zero disables boosting")
+	flag.DurationVar(&cfg.opts.Aging, "aging", 0, "waiting time per priority raise (priority); zero disables aging")
+	flag.Int64Var(&cfg.opts.Seed, "seed", 1, "random number generator seed (lottery)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: schedsim -workload file [flags]\n\nflags:\n")
+		flag.PrintDefaults()
+		fmt.Fprintf(flag.CommandLine.Output(), "\npolicies: %s\n", strings.Join(scheduler.Names(), ", "))
+	}
+	flag.Parse()
+	cfg.policies = strings.Split(policies, ",")
+
+	if err := run(os.Stdout, cfg); err != nil {
+		fmt.Fprintln(os.Stderr, "schedsim:", err)
+		os.Exit(1)
+	}
+}
+
+// run loads the workload and writes the comparison of the policies to w.
+func run(w io.Writer, cfg config) error {
+	if cfg.workload == "" {
+		return errNoWorkload
+	}
+	if cfg.cpus < 1 {
+		return errNoCPUs
+	}
+	if cfg.parallel && cfg.tickless {
+		return errEngines
+	}
+	if cfg.opts.SwitchCost < 0 {
+		return errSwitchCost
+	}
+	wl, err := workload.Load(cfg.workload)
+	if err != nil {
+		return err
+	}
+	reports := make([]metrics.Report, len(cfg.policies))
+	recorders := make([]*timeline.Recorder, len(cfg.policies))
+	for i, name := range cfg.policies {
+		reports[i], recorders[i], err = simulate(strings.TrimSpace(name), wl, cfg)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	if err := metrics.WriteComparison(w, cfg.policies, reports); err != nil {
+		return err
+	}
+	if !cfg.gantt {
+		return nil
+	}
+	for i, name := range cfg.policies {
+		fmt.Fprintf(w, "\n%s:\n", name)
+		if err := recorders[i].WriteGantt(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// simulate runs a fresh copy of the workload through the named policy,
+// and returns its metrics and timeline.
+func simulate(name string, wl workload.Workload, cfg config) (metrics.Report, *timeline.Recorder, error) {
+	cpus := cpu.NewCPUs(cfg.cpus)
+	sched, err := scheduler.New(name, cpus, cfg.opts)
+	if err != nil {
+		return metrics.Report{}, nil, err
+	}
+	rec := timeline.New(cpus)
+	sys := system.New(sched, wl.Schedule())
+	sys.OnTick(rec.Record)
+	run := sys.Run
+	switch {
+	case cfg.parallel:
+		run = func() (job.Jobs, error) { return sys.RunParallel(cpus) }
+	case cfg.tickless:
+		run = func() (job.Jobs, error) { return sys.RunTickless(cpus) }
+	}
+	jobs, err := run()
+	if err != nil {
+		return metrics.Report{}, nil, err
+	}
+	return metrics.Summarize(jobs, cpus), rec, nil
+}
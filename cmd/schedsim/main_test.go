@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"dat320/lab4/scheduler"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.csv")
+	data := "arrival,estimated\n0ms,3ms\n1ms,2ms\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config{
+		workload: path,
+		policies: []string{"fifo", "rr"},
+		cpus:     1,
+		gantt:    true,
+		opts:     scheduler.Options{Quantum: time.Millisecond},
+	}
+	var out bytes.Buffer
+	if err := run(&out, cfg); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Makespan", "fifo:", "rr:", "CPU0 |"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("run() output is missing %q:\n%s", want, out.String())
+		}
+	}
+
+	cfg.policies = []string{"nosuchpolicy"}
+	if err := run(&out, cfg); err == nil {
+		t.Errorf("run() with unknown policy: got nil error")
+	}
+
+	cfg.policies = []string{"rr"}
+	for _, cpus := range []int{0, -1} {
+		bad := cfg
+		bad.cpus = cpus
+		if err := run(&out, bad); err != errNoCPUs {
+			t.Errorf("run() with %d CPUs: got %v, want %v", cpus, err, errNoCPUs)
+		}
+	}
+	cfg.opts.SwitchCost = -time.Millisecond
+	if err := run(&out, cfg); err != errSwitchCost {
+		t.Errorf("run() with negative switch cost: got %v, want %v", err, errSwitchCost)
+	}
+	cfg.opts.SwitchCost = time.Millisecond
+	if err := run(&out, cfg); err == nil {
+		t.Errorf("run() with switch cost equal to the quantum: got nil error")
+	}
+}
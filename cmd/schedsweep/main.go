@@ -0,0 +1,144 @@
+// Command schedsweep sweeps the quantum and ticket distribution of one or
+// more scheduling policies over a workload, and writes the turnaround and
+// response times of each simulation as CSV.
+//
+// Usage:
+//
+//	schedsweep -workload jobs.csv [-policies rr,stride] [-quanta 1ms,5ms,10ms] [-tickets equal,inverse] [-switch 0] [-cache 0] [-o sweep.csv]
+//
+// Ticket distributions apply to lottery and stride; see package sweep.
+package main
+
+import (
+	"dat320/lab4/scheduler/sweep"
+	"dat320/lab4/scheduler/workload"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	_ "dat320/lab4/scheduler/cfs"
+	_ "dat320/lab4/scheduler/fifo"
+	_ "dat320/lab4/scheduler/lottery"
+	_ "dat320/lab4/scheduler/mlfq"
+	_ "dat320/lab4/scheduler/priority"
+	_ "dat320/lab4/scheduler/rr"
+	_ "dat320/lab4/scheduler/rt"
+	_ "dat320/lab4/scheduler/sjf"
+	_ "dat320/lab4/scheduler/steal"
+	_ "dat320/lab4/scheduler/stride"
+)
+
+var (
+	errNoWorkload          = errors.New("missing -workload flag")
+	errUnknownDistribution = errors.New("unknown ticket distribution")
+)
+
+// distributions holds the ticket distributions selectable with -tickets.
+var distributions = map[string]sweep.Distribution{
+	"workload": sweep.Workload,
+	"equal":    sweep.Equal(100),
+	"linear":   sweep.Linear(100),
+	"inverse":  sweep.Inverse(1000),
+}
+
+func main() {
+	var (
+		cfg                       sweep.Config
+		path, out                 string
+		policies, quanta, tickets string
+	)
+	flag.StringVar(&path, "workload", "", "workload `file`; see package workload for the formats")
+	flag.StringVar(&out, "o", "", "output `file`; standard output if empty")
+	flag.StringVar(&policies, "policies", "rr,stride", "comma-separated `list` of policies")
+	flag.StringVar(&quanta, "quanta", "1ms,2ms,5ms,10ms,20ms,50ms", "comma-separated `list` of quanta")
+	flag.StringVar(&tickets, "tickets", "equal", "comma-separated `list` of ticket distributions: workload, equal, linear, inverse")
+	flag.IntVar(&cfg.CPUs, "cpus", 1, "number of CPUs")
+	flag.IntVar(&cfg.Workers, "workers", 0, "maximum number of parallel simulations; zero means GOMAXPROCS")
+	flag.DurationVar(&cfg.Options.SwitchCost, "switch", 0, "context switch cost charged to a CPU on every dispatch")
+	flag.IntVar(&cfg.Options.CacheSize, "cache", 0, "cache capacity of each CPU, in job sizes; zero disables caches")
+	flag.DurationVar(&cfg.Options.CacheWarmup, "warmup", 5*time.Millisecond, "time for a job's working set to become warm in a cache")
+	flag.IntVar(&cfg.Options.WarmSpeed, "warmspeed", 2, "speed of jobs whose working set is warm")
+	flag.Int64Var(&cfg.Options.Seed, "seed", 1, "random number generator seed (lottery)")
+	flag.Parse()
+
+	if err := run(path, out, policies, quanta, tickets, cfg); err != nil {
+		fmt.Fprintln(os.Stderr, "schedsweep:", err)
+		os.Exit(1)
+	}
+}
+
+// run parses the lists given on the command line, runs the sweep,
+// and writes the results to the output file.
+func run(path, out, policies, quanta, tickets string, cfg sweep.Config) (err error) {
+	if path == "" {
+		return errNoWorkload
+	}
+	cfg.Policies = split(policies)
+	if cfg.Quanta, err = parseQuanta(quanta); err != nil {
+		return err
+	}
+	if cfg.Tickets, err = parseDistributions(tickets); err != nil {
+		return err
+	}
+	wl, err := workload.Load(path)
+	if err != nil {
+		return err
+	}
+	points, err := sweep.Run(wl, cfg)
+	if err != nil {
+		return err
+	}
+	var w io.Writer = os.Stdout
+	if out != "" {
+		f, err := os.Create(out)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+		}()
+		w = f
+	}
+	return sweep.WriteCSV(w, points)
+}
+
+// split splits a comma-separated list, ignoring spaces and empty elements.
+func split(list string) []string {
+	var elems []string
+	for _, e := range strings.Split(list, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			elems = append(elems, e)
+		}
+	}
+	return elems
+}
+
+func parseQuanta(list string) ([]time.Duration, error) {
+	var quanta []time.Duration
+	for _, s := range split(list) {
+		q, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, err
+		}
+		quanta = append(quanta, q)
+	}
+	return quanta, nil
+}
+
+func parseDistributions(list string) ([]sweep.Distribution, error) {
+	var dists []sweep.Distribution
+	for _, name := range split(list) {
+		d, ok := distributions[name]
+		if !ok {
+			return nil, fmt.Errorf("%q: %w", name, errUnknownDistribution)
+		}
+		dists = append(dists, d)
+	}
+	return dists, nil
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"dat320/lab4/scheduler/sweep"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	path, out := filepath.Join(dir, "jobs.csv"), filepath.Join(dir, "sweep.csv")
+	if err := os.WriteFile(path, []byte("arrival,estimated\n0ms,3ms\n1ms,2ms\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := run(path, out, "rr, stride", "1ms,2ms", "equal,linear", sweep.Config{}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a header, two rr lines and four stride lines
+	if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) != 7 {
+		t.Errorf("output has %d lines, want 7:\n%s", len(lines), data)
+	}
+
+	for _, test := range []struct{ quanta, tickets string }{
+		{"1ms,fast", "equal"},
+		{"1ms", "nosuchdistribution"},
+	} {
+		if err := run(path, out, "stride", test.quanta, test.tickets, sweep.Config{}); err == nil {
+			t.Errorf("run() with quanta %q and tickets %q: got nil error", test.quanta, test.tickets)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package cpu
+
+import (
+	"dat320/lab4/scheduler/job"
+	"dat320/lab4/scheduler/system/systime"
+	"time"
+)
+
+// Cache models a CPU cache holding the working sets of the jobs that have
+// run on the CPU. A job's working set becomes warm after the job has run on
+// the CPU for the warm-up time; while warm, the job runs at the warm speed.
+// A job migrating to another CPU starts cold there, but its working set
+// remains in the old CPU's cache until evicted. When the working sets do not
+// fit in the cache's capacity, the least recently used ones are evicted.
+// When a job finishes, its working set is evicted from every cache in the
+// set created by SetCaches; a cache created by NewCache is a set of its own.
+// The other caches of the set evict the job only when their CPU is next
+// assigned a job or completes a tick, so that a CPU running its job ahead
+// of the scheduler (see CPU.Step) sees the same cache as it would if the
+// CPUs were ticked one after the other.
+// Capacity is measured in the same units as job.Job.Size.
+type Cache struct {
+	capacity  int
+	warmup    time.Duration
+	warmSpeed int
+	used      int
+	entries   map[*job.Job]*cacheEntry
+	now       uint64   // logical clock for LRU eviction
+	set       []*Cache // caches evicting the jobs finished on any of them
+	finished  job.Jobs // jobs finished on other CPUs of the set, to be evicted by flush
+}
+
+type cacheEntry struct {
+	size    int
+	warmth  time.Duration // time the job has run with its working set in this cache
+	lastUse uint64
+}
+
+// NewCache returns an empty cache with the given capacity, warm-up time
+// and speed of jobs with a warm working set.
+func NewCache(capacity int, warmup time.Duration, warmSpeed int) *Cache {
+	c := &Cache{
+		capacity:  capacity,
+		warmup:    warmup,
+		warmSpeed: warmSpeed,
+		entries:   make(map[*job.Job]*cacheEntry),
+	}
+	c.set = []*Cache{c}
+	return c
+}
+
+// SetCaches attaches a new cache with the given capacity, warm-up time and
+// warm speed to each of the CPUs. A job finishing on any of the CPUs is
+// evicted from all of the caches.
+func SetCaches(cpus []*CPU, capacity int, warmup time.Duration, warmSpeed int) {
+	set := make([]*Cache, len(cpus))
+	for i, p := range cpus {
+		set[i] = NewCache(capacity, warmup, warmSpeed)
+		set[i].set = set
+		p.SetCache(set[i])
+	}
+}
+
+// Warm returns true if the job's working set is warm in this cache.
+func (c *Cache) Warm(j *job.Job) bool {
+	e, ok := c.entries[j]
+	return ok && e.warmth >= c.warmup
+}
+
+// Used returns the total size of the working sets in this cache.
+func (c *Cache) Used() int {
+	return c.used
+}
+
+// run records that the job runs for one tick on the CPU owning this cache,
+// and returns the speed at which the job runs during the tick.
+func (c *Cache) run(j *job.Job) int {
+	c.now++
+	e, ok := c.entries[j]
+	if !ok {
+		if j.Size() > c.capacity {
+			// working set never fits; always cold
+			return job.DefaultCPUSpeed
+		}
+		for c.used+j.Size() > c.capacity {
+			c.evictLRU()
+		}
+		e = &cacheEntry{size: j.Size()}
+		c.entries[j] = e
+		c.used += e.size
+	}
+	e.lastUse = c.now
+	speed := job.DefaultCPUSpeed
+	if e.warmth >= c.warmup {
+		speed = c.warmSpeed
+	}
+	e.warmth += systime.TickDuration
+	return speed
+}
+
+// finish removes the working set of a finished job from this cache, and
+// marks it for removal from the other caches in this cache's set.
+func (c *Cache) finish(j *job.Job) {
+	for _, cache := range c.set {
+		if cache == c {
+			cache.evict(j)
+		} else {
+			cache.finished = append(cache.finished, j)
+		}
+	}
+}
+
+// flush removes the working sets of the jobs finished on other CPUs of the set.
+func (c *Cache) flush() {
+	for _, j := range c.finished {
+		c.evict(j)
+	}
+	c.finished = nil
+}
+
+// evict removes the job's working set from the cache.
+func (c *Cache) evict(j *job.Job) {
+	if e, ok := c.entries[j]; ok {
+		c.used -= e.size
+		delete(c.entries, j)
+	}
+}
+
+// evictLRU removes the least recently used working set from the cache.
+func (c *Cache) evictLRU() {
+	var lruJob *job.Job
+	var lru *cacheEntry
+	for j, e := range c.entries {
+		if lru == nil || e.lastUse < lru.lastUse {
+			lruJob, lru = j, e
+		}
+	}
+	c.used -= lru.size
+	delete(c.entries, lruJob)
+}
@@ -2,12 +2,27 @@ package cpu
 
 import (
 	"dat320/lab4/scheduler/job"
+	"dat320/lab4/scheduler/system/systime"
 	"fmt"
+	"math"
+	"time"
 )
 
 type CPU struct {
 	id      int
 	current *job.Job
+	queue   job.Jobs // per-CPU run queue; unused by schedulers with a shared queue
+	busy    time.Duration
+	// context switch cost charged on every dispatch, and the part of it
+	// still to be paid before the current job can make progress
+	switchCost time.Duration
+	overhead   time.Duration
+	switchTime time.Duration
+	switches   int
+	cache      *Cache // nil if cache effects are not modelled
+	// job run ahead of the scheduler by Step, and whether it finished
+	stepped  *job.Job
+	stepDone bool
 }
 
 func New(id int) *CPU {
@@ -22,22 +37,112 @@ func NewCPUs(num int) []*CPU {
 	return cpus
 }
 
+// Running returns the jobs currently running on the given CPUs.
+func Running(cpus []*CPU) job.Jobs {
+	running := make(job.Jobs, 0, len(cpus))
+	for _, p := range cpus {
+		if p.IsRunning() {
+			running = append(running, p.current)
+		}
+	}
+	return running
+}
+
 func (p *CPU) ID() int {
 	return p.id
 }
 
+// Assign assigns the job to this CPU, preempting the current job, if any.
+// Assigning the current job again has no effect. Dispatching a new job
+// costs the CPU its context switch cost before the job makes progress.
 func (p *CPU) Assign(job *job.Job) {
+	if p.cache != nil {
+		p.cache.flush()
+	}
+	if job == p.current {
+		return
+	}
+	if p.current != nil {
+		p.current.Preempted(p.id)
+	}
 	if job != nil {
 		job.Started(p.id)
+		p.switches++
+		p.overhead = p.switchCost
 	}
 	p.current = job
 }
 
+// SetContextSwitchCost sets the time this CPU spends switching to a newly
+// dispatched job, during which the job does not make progress. Policies
+// preempting jobs at every quantum require the cost to be less than the
+// quantum; see scheduler.CheckSwitchCost.
+func (p *CPU) SetContextSwitchCost(cost time.Duration) {
+	p.switchCost = cost
+}
+
+// ContextSwitchCost returns the time this CPU spends switching to a newly dispatched job.
+func (p *CPU) ContextSwitchCost() time.Duration {
+	return p.switchCost
+}
+
+// SwitchTime returns the total time this CPU has spent context switching.
+func (p *CPU) SwitchTime() time.Duration {
+	return p.switchTime
+}
+
+// ContextSwitches returns the number of jobs dispatched on this CPU.
+func (p *CPU) ContextSwitches() int {
+	return p.switches
+}
+
 // CurrentJob returns the job currently running on this CPU.
 func (p *CPU) CurrentJob() *job.Job {
 	return p.current
 }
 
+// Enqueue adds the job to the back of this CPU's run queue.
+func (p *CPU) Enqueue(job *job.Job) {
+	p.queue = append(p.queue, job)
+}
+
+// Dequeue removes and returns the job at the front of this CPU's run queue.
+// Dequeue returns nil if the queue is empty.
+func (p *CPU) Dequeue() *job.Job {
+	if len(p.queue) == 0 {
+		return nil
+	}
+	removedJob := p.queue[0]
+	p.queue = p.queue[1:]
+	return removedJob
+}
+
+// Steal removes and returns the job at the back of this CPU's run queue,
+// i.e. the job that would otherwise wait the longest on this CPU.
+// Steal returns nil if the queue is empty.
+func (p *CPU) Steal() *job.Job {
+	if len(p.queue) == 0 {
+		return nil
+	}
+	last := len(p.queue) - 1
+	stolenJob := p.queue[last]
+	p.queue = p.queue[:last]
+	return stolenJob
+}
+
+// QueueLen returns the number of jobs waiting in this CPU's run queue.
+func (p *CPU) QueueLen() int {
+	return len(p.queue)
+}
+
+// Load returns the number of jobs waiting in or running on this CPU.
+func (p *CPU) Load() int {
+	if p.IsRunning() {
+		return len(p.queue) + 1
+	}
+	return len(p.queue)
+}
+
 // IsRunning returns true if this CPU is running some job.
 // Otherwise, false is returned if the CPU is idle.
 func (p *CPU) IsRunning() bool {
@@ -45,16 +150,131 @@ func (p *CPU) IsRunning() bool {
 }
 
 // Tick runs the current job on this CPU for one clock tick;
-// returns true if current job is done.
+// returns true if current job is done. The CPU becomes idle if
+// the current job is done or blocks for I/O. If the job was already
+// run for the tick by Step, Tick only completes the tick.
 func (p *CPU) Tick() bool {
-	done := p.current.Tick()
+	if stepped := p.stepped; stepped != nil {
+		p.stepped = nil
+		if stepped != p.current {
+			panic("cpu: job reassigned between Step and Tick")
+		}
+		return p.settle(p.stepDone)
+	}
+	return p.settle(p.run())
+}
+
+// Step runs the current job for one tick ahead of the scheduler, so that
+// the CPUs of a simulation can run their jobs in parallel goroutines.
+// The next call to Tick completes the tick using the result of the step,
+// instead of running the job again; until then, the CPU keeps its current
+// job. The scheduler must not assign another job to the CPU before calling
+// Tick. Step has no effect on an idle CPU.
+func (p *CPU) Step() {
+	if p.current == nil {
+		return
+	}
+	if p.stepped != nil {
+		panic("cpu: Step called twice without Tick")
+	}
+	p.stepDone = p.run()
+	p.stepped = p.current
+}
+
+// Horizon returns the number of ticks until the current job finishes or
+// blocks for I/O, including the ticks still spent switching to the job.
+// Until then, ticking the CPU only advances the job; see Advance. Horizon
+// returns 1 if the CPU has a cache, since the job's speed may then change
+// at every tick, and math.MaxInt for an idle CPU.
+func (p *CPU) Horizon() int {
+	switch {
+	case p.current == nil:
+		return math.MaxInt
+	case p.cache != nil:
+		return 1
+	}
+	return p.switchingTicks() + p.current.TicksLeft()
+}
+
+// switchingTicks returns the number of ticks still spent switching to the current job.
+func (p *CPU) switchingTicks() int {
+	if p.overhead <= 0 {
+		return 0
+	}
+	return int((p.overhead + systime.TickDuration - 1) / systime.TickDuration)
+}
+
+// Advance runs the CPU for n ticks at once, as n calls to Tick would.
+// Advance panics unless n is less than the CPU's Horizon, and has
+// no effect on an idle CPU.
+func (p *CPU) Advance(n int) {
+	if n <= 0 || p.current == nil {
+		return
+	}
+	if n >= p.Horizon() {
+		panic("cpu: Advance past the current job's horizon")
+	}
+	switching := p.switchingTicks()
+	if switching > n {
+		switching = n
+	}
+	p.overhead -= time.Duration(switching) * systime.TickDuration
+	p.switchTime += time.Duration(switching) * systime.TickDuration
+	p.busy += time.Duration(n-switching) * systime.TickDuration
+	p.current.Advance(n - switching)
+}
+
+// run runs the current job for one tick, or spends the tick switching
+// to the job, and returns true if the job finished.
+func (p *CPU) run() bool {
+	if p.overhead > 0 {
+		// still switching to the current job
+		p.overhead -= systime.TickDuration
+		p.switchTime += systime.TickDuration
+		return false
+	}
+	p.busy += systime.TickDuration
+	if p.cache != nil {
+		p.current.SetSpeed(p.cache.run(p.current))
+	}
+	return p.current.Tick()
+}
+
+// settle marks the CPU as idle if the current job finished or blocked
+// in the tick just run, and returns done.
+func (p *CPU) settle(done bool) bool {
+	if p.cache != nil {
+		p.cache.flush()
+	}
 	if done {
+		if p.cache != nil {
+			p.cache.finish(p.current)
+		}
 		// current job is done; mark CPU as idle
 		p.current = nil
+	} else if p.current.IsBlocked() {
+		// current job is waiting for I/O; mark CPU as idle
+		p.current = nil
 	}
 	return done
 }
 
+// SetCache attaches the cache model to this CPU; the speed of the jobs
+// running on this CPU is then set according to the state of the cache.
+func (p *CPU) SetCache(cache *Cache) {
+	p.cache = cache
+}
+
+// Cache returns the cache model of this CPU, or nil if none is attached.
+func (p *CPU) Cache() *Cache {
+	return p.cache
+}
+
+// BusyTime returns the total time this CPU has spent running jobs.
+func (p *CPU) BusyTime() time.Duration {
+	return p.busy
+}
+
 func (p *CPU) Header() string {
 	return fmt.Sprintf("CPU%d", p.ID())
 }
@@ -0,0 +1,243 @@
+package cpu
+
+import (
+	"dat320/lab4/scheduler/job"
+	"dat320/lab4/scheduler/system/systime"
+	"testing"
+	"time"
+)
+
+const ms = time.Millisecond
+
+func TestAssignAccounting(t *testing.T) {
+	clk := &systime.ManualClock{}
+	a, b := job.New(0, 10*ms), job.New(0, 10*ms)
+	a.Scheduled(clk)
+	b.Scheduled(clk)
+	p := New(0)
+
+	clk.Time = 3 * ms
+	p.Assign(a)
+	p.Tick()
+	p.Tick()
+	clk.Time = 5 * ms
+	p.Assign(b) // preempts a
+	p.Assign(b) // no effect
+	clk.Time = 7 * ms
+	p.Assign(a) // preempts b
+
+	if got, want := a.WaitingTime(), 5*ms; got != want {
+		t.Errorf("a.WaitingTime() = %v, want %v", got, want)
+	}
+	if got, want := b.WaitingTime(), 5*ms; got != want {
+		t.Errorf("b.WaitingTime() = %v, want %v", got, want)
+	}
+	if got, want := a.RunTime(), 2*ms; got != want {
+		t.Errorf("a.RunTime() = %v, want %v", got, want)
+	}
+	if a.ContextSwitches() != 1 || b.ContextSwitches() != 1 {
+		t.Errorf("ContextSwitches() = (%d, %d), want (1, 1)", a.ContextSwitches(), b.ContextSwitches())
+	}
+	if got := p.ContextSwitches(); got != 3 {
+		t.Errorf("p.ContextSwitches() = %d, want 3", got)
+	}
+
+	// moving a job directly to another CPU is not a preemption
+	q := New(1)
+	q.Assign(a)
+	p.Assign(nil)
+	if got := a.ContextSwitches(); got != 1 {
+		t.Errorf("after migration: a.ContextSwitches() = %d, want 1", got)
+	}
+}
+
+func TestContextSwitchCost(t *testing.T) {
+	clk := &systime.ManualClock{}
+	j := job.New(0, 2*ms)
+	j.Scheduled(clk)
+	p := New(0)
+	p.SetContextSwitchCost(2 * ms)
+	p.Assign(j)
+	ticks := 0
+	for done := false; !done; ticks++ {
+		done = p.Tick()
+	}
+	if ticks != 4 {
+		t.Errorf("job of 2ms finished after %d ticks, want 4", ticks)
+	}
+	if p.SwitchTime() != 2*ms || p.BusyTime() != 2*ms {
+		t.Errorf("SwitchTime() = %v, BusyTime() = %v, want 2ms each", p.SwitchTime(), p.BusyTime())
+	}
+}
+
+// runTicks runs the job on the CPU for n ticks, or until it finishes.
+func runTicks(p *CPU, j *job.Job, n int) {
+	p.Assign(j)
+	for i := 0; i < n && p.IsRunning(); i++ {
+		p.Tick()
+	}
+}
+
+func TestStep(t *testing.T) {
+	clk := &systime.ManualClock{}
+	j := job.New(0, 2*ms)
+	j.Scheduled(clk)
+	p := New(0)
+	p.Assign(j)
+
+	p.Step()
+	if !p.IsRunning() || j.Remaining() != 1*ms {
+		t.Fatalf("after Step(): IsRunning() = %t, Remaining() = %v; want true, 1ms", p.IsRunning(), j.Remaining())
+	}
+	if p.Tick() || j.Remaining() != 1*ms {
+		t.Fatalf("Tick() after Step() ran the job again: Remaining() = %v", j.Remaining())
+	}
+	p.Step()
+	if !p.IsRunning() {
+		t.Errorf("after last Step(): IsRunning() = false, want true until Tick()")
+	}
+	if !p.Tick() || p.IsRunning() {
+		t.Errorf("Tick() after last Step(): want job done and CPU idle")
+	}
+	p.Step() // no effect on an idle CPU
+	k := job.New(0, 2*ms)
+	k.Scheduled(clk)
+	p.Assign(k)
+	if p.Tick(); k.Remaining() != 1*ms {
+		t.Errorf("Tick() after Step() on idle CPU: Remaining() = %v, want 1ms", k.Remaining())
+	}
+}
+
+func TestCacheWarmup(t *testing.T) {
+	clk := &systime.ManualClock{}
+	j := job.New(1, 10*ms)
+	j.Scheduled(clk)
+	p, q := New(0), New(1)
+	p.SetCache(NewCache(2, 2*ms, 2))
+	q.SetCache(NewCache(2, 2*ms, 2))
+
+	// two cold ticks, then one warm tick at double speed
+	runTicks(p, j, 3)
+	if got, want := j.Remaining(), 6*ms; got != want {
+		t.Errorf("after 3 ticks on p: Remaining() = %v, want %v", got, want)
+	}
+	if !p.Cache().Warm(j) {
+		t.Error("p.Cache().Warm(j) = false after warm-up, want true")
+	}
+	// migrating to q starts cold again
+	p.Assign(nil)
+	runTicks(q, j, 2)
+	if got, want := j.Remaining(), 4*ms; got != want {
+		t.Errorf("after 2 ticks on q: Remaining() = %v, want %v", got, want)
+	}
+	// the working set is still warm in p's cache; 4ms takes 2 ticks
+	q.Assign(nil)
+	runTicks(p, j, 2)
+	if p.IsRunning() {
+		t.Errorf("job still running on p with %v remaining, want finished", j.Remaining())
+	}
+	if got := p.Cache().Used(); got != 0 {
+		t.Errorf("p.Cache().Used() = %d after job finished, want 0", got)
+	}
+}
+
+func TestCacheEviction(t *testing.T) {
+	clk := &systime.ManualClock{}
+	jobs := job.Jobs{job.New(1, time.Second), job.New(1, time.Second), job.New(1, time.Second)}
+	p := New(0)
+	p.SetCache(NewCache(2, ms, 2))
+	for _, j := range jobs {
+		j.Scheduled(clk)
+		runTicks(p, j, 2)
+	}
+	// the first job was least recently used, and was evicted for the third
+	if p.Cache().Warm(jobs[0]) || !p.Cache().Warm(jobs[1]) || !p.Cache().Warm(jobs[2]) {
+		t.Errorf("Warm() = %t, %t, %t; want false, true, true",
+			p.Cache().Warm(jobs[0]), p.Cache().Warm(jobs[1]), p.Cache().Warm(jobs[2]))
+	}
+	if got := p.Cache().Used(); got != 2 {
+		t.Errorf("Used() = %d, want 2", got)
+	}
+	big := job.New(3, time.Second)
+	big.Scheduled(clk)
+	runTicks(p, big, 5)
+	if p.Cache().Warm(big) {
+		t.Error("Warm(big) = true for job larger than the cache, want false")
+	}
+}
+
+func TestCacheSameIDs(t *testing.T) {
+	clk := &systime.ManualClock{}
+	// jobs from different factories may have the same ID
+	a, b := job.NewFactory().New(1, time.Second), job.NewFactory().New(1, time.Second)
+	a.Scheduled(clk)
+	b.Scheduled(clk)
+	p := New(0)
+	p.SetCache(NewCache(2, ms, 2))
+	runTicks(p, a, 2)
+	if p.Cache().Warm(b) {
+		t.Error("Warm(b) = true after running a with the same ID, want false")
+	}
+	runTicks(p, b, 1)
+	if got := p.Cache().Used(); got != 2 {
+		t.Errorf("Used() = %d, want 2", got)
+	}
+}
+
+func TestSetCaches(t *testing.T) {
+	clk := &systime.ManualClock{}
+	j := job.New(1, 4*ms)
+	j.Scheduled(clk)
+	cpus := NewCPUs(2)
+	SetCaches(cpus, 2, ms, 2)
+	p, q := cpus[0], cpus[1]
+	runTicks(p, j, 1)
+	p.Assign(nil)
+	// the job finishes on q, and is evicted from p's cache
+	// when p is next assigned a job
+	runTicks(q, j, 4)
+	if q.IsRunning() {
+		t.Fatalf("job still running on q with %v remaining, want finished", j.Remaining())
+	}
+	if got := q.Cache().Used(); got != 0 {
+		t.Errorf("q: Cache().Used() = %d after job finished, want 0", got)
+	}
+	if got := p.Cache().Used(); got != 1 {
+		t.Errorf("p: Cache().Used() = %d before p is assigned a job, want 1", got)
+	}
+	p.Assign(nil)
+	if got := p.Cache().Used(); got != 0 {
+		t.Errorf("p: Cache().Used() = %d after job finished, want 0", got)
+	}
+}
+
+func TestSetCachesStep(t *testing.T) {
+	// tick runs a tick in which job j finishes on p while job k starts on q,
+	// whose cache is full, and returns whether job l is still warm in q's cache
+	tick := func(step bool) bool {
+		clk := &systime.ManualClock{}
+		j, k, l := job.New(1, 2*ms), job.New(1, 4*ms), job.New(1, 4*ms)
+		for _, j := range []*job.Job{j, k, l} {
+			j.Scheduled(clk)
+		}
+		cpus := NewCPUs(2)
+		SetCaches(cpus, 2, ms, 2)
+		p, q := cpus[0], cpus[1]
+		runTicks(q, l, 1)
+		runTicks(q, j, 1)
+		p.Assign(j)
+		q.Assign(k)
+		if step {
+			p.Step()
+			q.Step()
+		}
+		if !p.Tick() {
+			t.Fatalf("job still running on p with %v remaining, want finished", j.Remaining())
+		}
+		q.Tick()
+		return q.Cache().Warm(l)
+	}
+	if got, want := tick(true), tick(false); got != want {
+		t.Errorf("Warm() = %t after Step and Tick, want %t as after Tick", got, want)
+	}
+}
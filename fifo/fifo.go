@@ -1,22 +1,35 @@
 package fifo
 
 import (
+	"dat320/lab4/scheduler"
 	"dat320/lab4/scheduler/cpu"
 	"dat320/lab4/scheduler/job"
 	"time"
 )
 
+// fifo dispatches jobs from a single queue shared by all CPUs.
 type fifo struct {
-	cpu   *cpu.CPU
+	cpus  []*cpu.CPU
 	queue job.Jobs
 }
 
+func init() {
+	scheduler.Register("fifo", func(cpus []*cpu.CPU, _ scheduler.Options) (scheduler.Scheduler, error) {
+		return New(cpus), nil
+	})
+}
+
+var (
+	_ scheduler.Scheduler = (*fifo)(nil)
+	_ scheduler.Tickless  = (*fifo)(nil)
+)
+
 func New(cpus []*cpu.CPU) *fifo {
-	if len(cpus) != 1 {
-		panic("fifo scheduler supports only a single CPU")
+	if len(cpus) == 0 {
+		panic("fifo scheduler requires at least one CPU")
 	}
 	return &fifo{
-		cpu:   cpus[0],
+		cpus:  cpus,
 		queue: make(job.Jobs, 0),
 	}
 }
@@ -34,23 +47,39 @@ func (f *fifo) getNewJob() *job.Job {
 	return removedJob
 }
 
-// reassign finds a new job to run on this CPU
-func (f *fifo) reassign() {
+// reassign finds a new job to run on the given CPU
+func (f *fifo) reassign(c *cpu.CPU) {
 	nxtJob := f.getNewJob()
-	f.cpu.Assign(nxtJob)
+	c.Assign(nxtJob)
 }
 
+// Tick runs the jobs on all CPUs for one tick and assigns a new job
+// to every CPU that is idle, and returns the number of jobs finished.
 func (f *fifo) Tick(systemTime time.Duration) int {
 	jobsFinished := 0
-	if f.cpu.IsRunning() {
-		if f.cpu.Tick() {
+	for _, c := range f.cpus {
+		if c.IsRunning() && c.Tick() {
 			jobsFinished++
-			f.reassign()
 		}
-	} else {
-		//f.Tick(systemTime)
-		// CPU is idle, find new job in own queue
-		f.reassign()
+		if !c.IsRunning() {
+			// CPU is idle, find new job in the shared queue
+			f.reassign(c)
+		}
 	}
 	return jobsFinished
 }
+
+// NextDecision returns scheduler.Never, since jobs run to completion.
+func (f *fifo) NextDecision(now time.Duration) time.Duration {
+	return scheduler.Never
+}
+
+// Len returns the number of jobs waiting in the queue.
+func (f *fifo) Len() int {
+	return len(f.queue)
+}
+
+// Running returns the jobs currently running on the CPUs.
+func (f *fifo) Running() job.Jobs {
+	return cpu.Running(f.cpus)
+}
@@ -0,0 +1,33 @@
+package fifo
+
+import (
+	"dat320/lab4/scheduler/cpu"
+	"dat320/lab4/scheduler/job"
+	"dat320/lab4/scheduler/system/systime"
+	"testing"
+	"time"
+)
+
+func TestFifoMultiCPU(t *testing.T) {
+	const ms = time.Millisecond
+	clk := &systime.ManualClock{}
+	jobs := job.Jobs{job.New(0, 3*ms), job.New(0, 2*ms), job.New(0, 1*ms)}
+	f := New(cpu.NewCPUs(2))
+	for _, j := range jobs {
+		j.Scheduled(clk)
+		f.Add(j)
+	}
+	for finished := 0; finished < len(jobs); clk.Time += ms {
+		finished += f.Tick(clk.Time)
+	}
+	wantTurnaround := []time.Duration{3 * ms, 2 * ms, 3 * ms}
+	wantResponse := []time.Duration{0, 0, 2 * ms}
+	for i, j := range jobs {
+		if got := j.TurnaroundTime(); got != wantTurnaround[i] {
+			t.Errorf("job %s: TurnaroundTime() = %v, want %v", j.Name(), got, wantTurnaround[i])
+		}
+		if got := j.ResponseTime(); got != wantResponse[i] {
+			t.Errorf("job %s: ResponseTime() = %v, want %v", j.Name(), got, wantResponse[i])
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package job
+
+import (
+	"sync"
+	"time"
+)
+
+// defaultFactory allocates the IDs of jobs created with New and NewIO.
+var defaultFactory = NewFactory()
+
+// Factory creates jobs with IDs 1, 2, 3, and so on, named A, B, C, and so on.
+// Each simulation should create its jobs with its own factory, so that
+// simulations running in parallel goroutines do not share IDs, and jobs
+// are named the same way in every simulation.
+// A Factory is safe for concurrent use.
+type Factory struct {
+	mu     sync.Mutex
+	nextID int
+}
+
+// NewFactory returns a factory whose first job has ID 1.
+func NewFactory() *Factory {
+	return &Factory{}
+}
+
+// New returns a job with given working set size and estimated running time.
+func (f *Factory) New(size int, estimated time.Duration) *Job {
+	return newJob(f.allocate(), size, estimated)
+}
+
+// NewIO returns a job alternating between CPU and I/O bursts; see NewIO.
+func (f *Factory) NewIO(size int, bursts ...time.Duration) *Job {
+	return newIO(f.allocate(), size, bursts)
+}
+
+// Reset makes the factory allocate IDs from 1 again.
+func (f *Factory) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.nextID = 0
+}
+
+func (f *Factory) allocate() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.nextID++
+	return f.nextID
+}
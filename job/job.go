@@ -6,11 +6,10 @@ import (
 	"time"
 )
 
-var nextID = 0
-
 const (
 	NotStartedYet   = -1
 	DefaultCPUSpeed = 1
+	notRunning      = -1
 )
 
 // Job keeps track of when the job arrived, was started, its remaining time,
@@ -26,16 +25,29 @@ type Job struct {
 	start     time.Duration
 	finished  time.Duration
 	remaining time.Duration
+	runTime   time.Duration // time spent running on a CPU
+	waiting   time.Duration // time spent waiting in a run queue
+	readyAt   time.Duration // when the job last entered a run queue
+	switches  int           // number of times the job was preempted
+	cpu       int           // ID of the CPU running the job, or notRunning
+	io        ioBursts      // I/O behavior; zero for CPU-bound jobs
+	observers []Observer    // notified of lifecycle events
 	systime.SystemTime
-	Stride  int
-	Pass    int
-	Tickets int
+	Stride   int
+	Pass     int
+	Tickets  int
+	Nice     int           // niceness in [-20, 19]; lower is higher priority
+	VRuntime time.Duration // virtual runtime weighted by niceness
+	Priority int           // static priority; lower is higher priority
+	Deadline time.Duration // absolute deadline of a real-time job; zero if none
+	Period   time.Duration // release period of a real-time job; zero if none
 }
 
 // New returns a job with given working set size and estimated running time.
+// Jobs created with New share IDs with all other jobs created with New in the
+// process; use a Factory to allocate IDs for a single simulation.
 func New(size int, estimated time.Duration) *Job {
-	nextID++
-	return newJob(nextID, size, estimated)
+	return defaultFactory.New(size, estimated)
 }
 
 // newJob returns a job with given working set size and estimated running time.
@@ -47,6 +59,7 @@ func newJob(id, size int, estimated time.Duration) *Job {
 		estimated: estimated,
 		remaining: estimated,
 		start:     NotStartedYet,
+		cpu:       notRunning,
 	}
 }
 
@@ -76,6 +89,11 @@ func (j Job) Remaining() time.Duration {
 	return j.remaining
 }
 
+// Estimated returns the job's estimated running time.
+func (j Job) Estimated() time.Duration {
+	return j.estimated
+}
+
 // ID returns the job's ID.
 func (j Job) ID() int {
 	return j.id
@@ -86,8 +104,9 @@ func (j Job) Size() int {
 	return j.size
 }
 
+// ResetJobCounter makes New allocate job IDs from 1 again.
 func ResetJobCounter() {
-	nextID = 0
+	defaultFactory.Reset()
 }
 
 // SetSpeed sets the job's current speed.
@@ -97,19 +116,52 @@ func (j *Job) SetSpeed(speed int) {
 
 // run runs the job for the given duration.
 func (j *Job) run(durationToRun time.Duration) bool {
+	if j.io.bursts != nil {
+		return j.runBurst(durationToRun)
+	}
 	j.remaining -= durationToRun
 	return j.remaining <= 0
 }
 
 // Tick runs the job for one tick and returns true if job is finished.
 func (j *Job) Tick() bool {
+	j.runTime += systime.TickDuration
 	done := j.run(systime.TickDuration * time.Duration(j.speed))
 	if done {
 		j.finished = j.Now()
+		cpuID := j.cpu
+		j.cpu = notRunning
+		j.emit(Finished, cpuID)
 	}
 	return done
 }
 
+// TicksLeft returns the number of ticks, at the job's current speed,
+// until the job finishes or blocks for I/O.
+func (j Job) TicksLeft() int {
+	left := j.remaining
+	if j.io.bursts != nil {
+		left = j.io.cpuLeft
+	}
+	perTick := systime.TickDuration * time.Duration(j.speed)
+	return int((left + perTick - 1) / perTick)
+}
+
+// Advance runs the job for n ticks at once, as n calls to Tick would.
+// Advance panics unless the job runs for more than n ticks before it
+// finishes or blocks; see TicksLeft.
+func (j *Job) Advance(n int) {
+	if n >= j.TicksLeft() {
+		panic("job: Advance past the end of the job's CPU burst")
+	}
+	j.runTime += time.Duration(n) * systime.TickDuration
+	ran := time.Duration(n) * systime.TickDuration * time.Duration(j.speed)
+	j.remaining -= ran
+	if j.io.bursts != nil {
+		j.io.cpuLeft -= ran
+	}
+}
+
 // Equal returns true if this job and the given job has the same id.
 func (j Job) Equal(job Job) bool {
 	return j.id == job.id
@@ -0,0 +1,86 @@
+package job
+
+import (
+	"fmt"
+	"time"
+)
+
+// Kind is the kind of a job lifecycle event.
+type Kind int
+
+const (
+	Arrived    Kind = iota // the job arrived in the system
+	Dispatched             // the job was assigned to a CPU
+	Preempted              // the job was taken off a CPU before it finished
+	Blocked                // the job blocked for I/O
+	Unblocked              // the job's I/O completed
+	Finished               // the job finished
+)
+
+var kindNames = [...]string{"arrived", "dispatched", "preempted", "blocked", "unblocked", "finished"}
+
+func (k Kind) String() string {
+	if k < 0 || int(k) >= len(kindNames) {
+		return fmt.Sprintf("Kind(%d)", int(k))
+	}
+	return kindNames[k]
+}
+
+// MarshalText encodes the kind as its name, for example in JSON.
+func (k Kind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText decodes the name of a kind.
+func (k *Kind) UnmarshalText(text []byte) error {
+	for i, name := range kindNames {
+		if name == string(text) {
+			*k = Kind(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown event kind %q", text)
+}
+
+// Event is a state transition of a job.
+type Event struct {
+	Kind  Kind          `json:"kind"`
+	Time  time.Duration `json:"time"`
+	JobID int           `json:"job_id"`
+	Job   string        `json:"job"` // name of the job
+	CPU   int           `json:"cpu"` // CPU the job was dispatched to or left; -1 for other events
+}
+
+// Observer is notified of the lifecycle events of the jobs it subscribes to.
+// Events are emitted by the job's methods, so dispatch and preemption events
+// originate from cpu.CPU.Assign.
+type Observer interface {
+	Observe(Event)
+}
+
+// ObserverFunc is a function used as an Observer.
+type ObserverFunc func(Event)
+
+// Observe calls f(e).
+func (f ObserverFunc) Observe(e Event) {
+	f(e)
+}
+
+// Subscribe adds an observer to be notified of the job's lifecycle events.
+func (j *Job) Subscribe(o Observer) {
+	j.observers = append(j.observers, o)
+}
+
+// emit notifies the job's observers of an event of the given kind.
+func (j *Job) emit(kind Kind, cpuID int) {
+	if len(j.observers) == 0 {
+		return
+	}
+	e := Event{Kind: kind, JobID: j.id, Job: j.Name(), CPU: cpuID}
+	if j.SystemTime != nil {
+		e.Time = j.Now()
+	}
+	for _, o := range j.observers {
+		o.Observe(e)
+	}
+}
@@ -0,0 +1,108 @@
+package job
+
+import (
+	"dat320/lab4/scheduler/system/systime"
+	"time"
+)
+
+// ioBursts holds the state of a job alternating between CPU and I/O bursts.
+type ioBursts struct {
+	bursts    []time.Duration // remaining bursts after the current one; I/O first
+	cpuLeft   time.Duration   // time left of the current CPU burst
+	ioLeft    time.Duration   // time left of the current I/O burst
+	blocked   bool            // true while waiting for I/O
+	blockings int             // number of times the job blocked for I/O
+}
+
+// NewIO returns a job with the given working set size that alternates between
+// CPU bursts and I/O bursts, starting and ending with a CPU burst:
+//
+//	NewIO(size, cpu1, io1, cpu2, io2, cpu3)
+//
+// The job's estimated running time is the total time of its CPU bursts.
+// NewIO panics if the number of bursts is even.
+func NewIO(size int, bursts ...time.Duration) *Job {
+	return defaultFactory.NewIO(size, bursts...)
+}
+
+// newIO returns a job with the given ID that alternates between CPU and I/O bursts.
+func newIO(id, size int, bursts []time.Duration) *Job {
+	if len(bursts)%2 == 0 {
+		panic("job: NewIO requires an odd number of bursts, starting and ending with a CPU burst")
+	}
+	var estimated time.Duration
+	for i := 0; i < len(bursts); i += 2 {
+		estimated += bursts[i]
+	}
+	j := newJob(id, size, estimated)
+	j.io.cpuLeft = bursts[0]
+	j.io.bursts = append([]time.Duration{}, bursts[1:]...)
+	return j
+}
+
+// runBurst runs the current CPU burst for the given duration, and blocks the
+// job if the burst is done. Returns true if the job is finished.
+func (j *Job) runBurst(durationToRun time.Duration) bool {
+	if durationToRun > j.io.cpuLeft {
+		durationToRun = j.io.cpuLeft
+	}
+	j.io.cpuLeft -= durationToRun
+	j.remaining -= durationToRun
+	if j.io.cpuLeft > 0 || len(j.io.bursts) == 0 {
+		return j.remaining <= 0
+	}
+	// CPU burst done; block for the next I/O burst
+	j.io.ioLeft, j.io.cpuLeft = j.io.bursts[0], j.io.bursts[1]
+	j.io.bursts = j.io.bursts[2:]
+	j.io.blocked = true
+	j.io.blockings++
+	cpuID := j.cpu
+	j.cpu = notRunning
+	j.emit(Blocked, cpuID)
+	return false
+}
+
+// IsBlocked returns true if the job is waiting for I/O.
+func (j Job) IsBlocked() bool {
+	return j.io.blocked
+}
+
+// Blockings returns the number of times the job has blocked for I/O.
+func (j Job) Blockings() int {
+	return j.io.blockings
+}
+
+// IOTick advances the job's current I/O burst by one tick. Returns true
+// if the I/O completed and the job is ready to run again.
+func (j *Job) IOTick() bool {
+	if !j.io.blocked {
+		return false
+	}
+	j.io.ioLeft -= systime.TickDuration
+	if j.io.ioLeft > 0 {
+		return false
+	}
+	j.io.blocked = false
+	j.readyAt = j.Now()
+	j.emit(Unblocked, notRunning)
+	return true
+}
+
+// IOTicksLeft returns the number of ticks until the job's I/O completes,
+// or 0 if the job is not blocked.
+func (j Job) IOTicksLeft() int {
+	if !j.io.blocked {
+		return 0
+	}
+	return int((j.io.ioLeft + systime.TickDuration - 1) / systime.TickDuration)
+}
+
+// IOAdvance advances the job's current I/O burst by n ticks at once, as n
+// calls to IOTick would. IOAdvance panics unless the I/O runs for more than
+// n ticks; see IOTicksLeft.
+func (j *Job) IOAdvance(n int) {
+	if n >= j.IOTicksLeft() {
+		panic("job: IOAdvance past the end of the job's I/O burst")
+	}
+	j.io.ioLeft -= time.Duration(n) * systime.TickDuration
+}
@@ -9,14 +9,41 @@ func (j *Job) Scheduled(s systime.SystemTime) {
 	//j.SystemTime = s
 	j.SystemTime = s
 	j.arrival = j.SystemTime.Now()
+	j.readyAt = j.arrival
+	j.emit(Arrived, notRunning)
 	// (student) implement task 2.1
 }
 func (j *Job) Started(cpuID int) {
 	// (student) implement task 2.2
 	//j.id = cpuID
-	if j.start == NotStartedYet {
-		j.start = j.SystemTime.Now() //for fifo
+	// a job that was never scheduled, such as a test job, has no times to record
+	if j.SystemTime != nil {
+		if j.start == NotStartedYet {
+			j.start = j.SystemTime.Now() //for fifo
+		}
+		// only count waiting time if coming from a run queue;
+		// a job moved directly from another CPU has not been waiting
+		if j.cpu == notRunning {
+			j.waiting += j.Now() - j.readyAt
+		}
 	}
+	j.cpu = cpuID
+	j.emit(Dispatched, cpuID)
+}
+
+// Preempted records that the job was taken off the given CPU before it
+// finished, and is waiting to run again. Preempted has no effect if the
+// job has already been moved to another CPU.
+func (j *Job) Preempted(cpuID int) {
+	if j.cpu != cpuID {
+		return
+	}
+	j.cpu = notRunning
+	j.switches++
+	if j.SystemTime != nil {
+		j.readyAt = j.Now()
+	}
+	j.emit(Preempted, cpuID)
 }
 func (j Job) TurnaroundTime() time.Duration {
 	r := j.finished - j.arrival
@@ -28,3 +55,28 @@ func (j Job) ResponseTime() time.Duration {
 	r := j.start - j.arrival //fifo
 	return r
 }
+
+// Arrival returns the time the job arrived in the system.
+func (j Job) Arrival() time.Duration {
+	return j.arrival
+}
+
+// Finished returns the time the job finished.
+func (j Job) Finished() time.Duration {
+	return j.finished
+}
+
+// WaitingTime returns the total time the job has spent waiting in a run queue.
+func (j Job) WaitingTime() time.Duration {
+	return j.waiting
+}
+
+// RunTime returns the total time the job has spent running on a CPU.
+func (j Job) RunTime() time.Duration {
+	return j.runTime
+}
+
+// ContextSwitches returns the number of times the job was preempted.
+func (j Job) ContextSwitches() int {
+	return j.switches
+}
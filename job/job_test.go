@@ -0,0 +1,125 @@
+package job
+
+import (
+	"dat320/lab4/scheduler/system/systime"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestName(t *testing.T) {
+	tests := []struct {
+		id   int
+		want string
+	}{
+		{-1, "-1"},
+		{0, "0"},
+		{1, "A"},
+		{26, "Z"},
+		{27, "a"},
+		{52, "z"},
+		{53, "AA"},
+		{54, "AB"},
+		{104, "Az"},
+		{105, "BA"},
+		{52*52 + 52, "zz"},
+		{52*52 + 53, "AAA"},
+	}
+	for _, test := range tests {
+		if got := toLetter(test.id); got != test.want {
+			t.Errorf("toLetter(%d) = %q, want %q", test.id, got, test.want)
+		}
+	}
+}
+
+func TestFactory(t *testing.T) {
+	const perGoroutine = 100
+	f := NewFactory()
+	ids := make(chan int, 4*perGoroutine)
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perGoroutine; i++ {
+				ids <- f.New(0, time.Millisecond).ID()
+			}
+		}()
+	}
+	wg.Wait()
+	close(ids)
+	seen := make(map[int]bool)
+	for id := range ids {
+		if seen[id] || id < 1 || id > 4*perGoroutine {
+			t.Fatalf("Factory.New() allocated ID %d twice or out of range", id)
+		}
+		seen[id] = true
+	}
+
+	// factories allocate independently of each other and of New
+	other := NewFactory()
+	New(0, time.Millisecond)
+	if j := other.NewIO(0, time.Millisecond); j.Name() != "A" {
+		t.Errorf("first job of a new factory is named %s, want A", j.Name())
+	}
+	f.Reset()
+	if j := f.New(0, time.Millisecond); j.ID() != 1 {
+		t.Errorf("first job after Reset() has ID %d, want 1", j.ID())
+	}
+}
+
+func TestJobsHas(t *testing.T) {
+	a, b := NewFactory().New(0, time.Millisecond), NewFactory().New(0, time.Millisecond)
+	if js := (Jobs{a}); !js.Has(a) || js.Has(b) {
+		t.Errorf("Jobs{a}.Has(a), Has(b) = %t, %t; want true, false for jobs with the same ID", js.Has(a), js.Has(b))
+	}
+}
+
+func TestAdvance(t *testing.T) {
+	const ms = time.Millisecond
+	ticked, advanced := NewIO(0, 5*ms, 3*ms, 2*ms), NewIO(0, 5*ms, 3*ms, 2*ms)
+	ticked.Scheduled(&systime.ManualClock{})
+	advanced.Scheduled(&systime.ManualClock{})
+	ticked.SetSpeed(2)
+	advanced.SetSpeed(2)
+	if got := advanced.TicksLeft(); got != 3 {
+		t.Fatalf("TicksLeft() = %d, want 3 at speed 2 for a 5ms burst", got)
+	}
+	ticked.Tick()
+	ticked.Tick()
+	advanced.Advance(2)
+	if ticked.Remaining() != advanced.Remaining() || ticked.RunTime() != advanced.RunTime() {
+		t.Errorf("Advance(2): Remaining() = %v, RunTime() = %v; want %v, %v as after two ticks",
+			advanced.Remaining(), advanced.RunTime(), ticked.Remaining(), ticked.RunTime())
+	}
+	if advanced.Tick() || !advanced.IsBlocked() {
+		t.Fatalf("Tick() after Advance(2): want job blocked at the end of its burst")
+	}
+	if got := advanced.IOTicksLeft(); got != 3 {
+		t.Fatalf("IOTicksLeft() = %d, want 3", got)
+	}
+	advanced.IOAdvance(2)
+	if !advanced.IOTick() {
+		t.Errorf("IOTick() after IOAdvance(2) = false, want I/O completed")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Advance() past the end of the burst did not panic")
+		}
+	}()
+	advanced.Advance(advanced.TicksLeft())
+}
+
+func TestStartedWithoutSystemTime(t *testing.T) {
+	j := NewTestJob(1, time.Millisecond, time.Millisecond)
+	j.Started(0)
+	j.Preempted(0)
+	j.Started(1)
+	if got := j.ContextSwitches(); got != 1 {
+		t.Errorf("ContextSwitches() = %d, want 1", got)
+	}
+	if got := j.WaitingTime(); got != 0 {
+		t.Errorf("WaitingTime() = %v, want 0s", got)
+	}
+}
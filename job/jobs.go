@@ -8,10 +8,12 @@ import (
 // Jobs is a slice of jobs ordered according to some scheduling policies.
 type Jobs []*Job
 
-// Has returns true if the given job is in the jobs slice.
+// Has returns true if the given job is in the jobs slice. Jobs are compared
+// by identity rather than by ID, since jobs from different factories may
+// have the same ID.
 func (js Jobs) Has(job *Job) bool {
 	for _, j := range js {
-		if j.Equal(*job) {
+		if j == job {
 			return true
 		}
 	}
@@ -29,12 +31,22 @@ func (js Jobs) String() string {
 	return b.String()
 }
 
+// letters are the digits of job names.
+const letters = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// toLetter returns the name of the job with the given ID: A to Z for IDs 1 to
+// 26, a to z for IDs 27 to 52, and then AA, AB, and so on, counting in
+// bijective base 52. IDs below 1 are named by their number.
 func toLetter(id int) string {
-	if id >= 0 && id <= 26 {
-		return string(rune('A' - 1 + id))
+	if id <= 0 {
+		return strconv.Itoa(id)
+	}
+	var name []byte
+	for ; id > 0; id = (id - 1) / len(letters) {
+		name = append(name, letters[(id-1)%len(letters)])
 	}
-	if id >= 27 && id <= 53 {
-		return string(rune('a' - 27 + id))
+	for i, j := 0, len(name)-1; i < j; i, j = i+1, j-1 {
+		name[i], name[j] = name[j], name[i]
 	}
-	return strconv.Itoa(id)
+	return string(name)
 }
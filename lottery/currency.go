@@ -0,0 +1,46 @@
+package lottery
+
+import "dat320/lab4/scheduler/job"
+
+// Currency is a ticket currency funded by a number of base tickets.
+// Jobs holding tickets in a currency share its funding in proportion
+// to their tickets; the value of a currency's tickets is deflated as
+// more tickets are issued in it, isolating its jobs from other currencies.
+type Currency struct {
+	name    string
+	funding int
+}
+
+// NewCurrency returns a currency backed by the given number of base tickets.
+func NewCurrency(name string, funding int) *Currency {
+	return &Currency{name: name, funding: funding}
+}
+
+// Name returns the name of the currency.
+func (c *Currency) Name() string {
+	return c.name
+}
+
+// Funding returns the number of base tickets backing the currency.
+func (c *Currency) Funding() int {
+	return c.funding
+}
+
+// Fund changes the number of base tickets backing the currency by the given amount.
+func (c *Currency) Fund(tickets int) {
+	c.funding += tickets
+	if c.funding < 0 {
+		c.funding = 0
+	}
+}
+
+// issued returns the number of tickets issued in the currency to the given jobs.
+func (c *Currency) issued(jobs job.Jobs, currency map[*job.Job]*Currency) int {
+	n := 0
+	for _, j := range jobs {
+		if currency[j] == c {
+			n += j.Tickets
+		}
+	}
+	return n
+}
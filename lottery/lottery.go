@@ -0,0 +1,202 @@
+// Package lottery implements proportional-share lottery scheduling.
+// At every quantum boundary each CPU draws a winning ticket among the
+// runnable jobs, so that a job's share of the CPUs is proportional to
+// the value of the tickets it holds.
+package lottery
+
+import (
+	"dat320/lab4/scheduler"
+	"dat320/lab4/scheduler/cpu"
+	"dat320/lab4/scheduler/job"
+	"errors"
+	"math/rand"
+	"time"
+)
+
+// DefaultTickets is given to jobs added without any tickets.
+const DefaultTickets = 100
+
+var (
+	errNotEnoughTickets = errors.New("job does not hold enough tickets")
+	errInvalidTickets   = errors.New("invalid argument: tickets must be greater than 0")
+	errCurrencyMismatch = errors.New("jobs hold tickets in different currencies")
+)
+
+type lottery struct {
+	queue    job.Jobs
+	cpus     []*cpu.CPU
+	quantum  time.Duration
+	rng      *rand.Rand
+	currency map[*job.Job]*Currency // jobs not in the map hold base tickets
+}
+
+func init() {
+	scheduler.Register("lottery", func(cpus []*cpu.CPU, opts scheduler.Options) (scheduler.Scheduler, error) {
+		if err := scheduler.RequireQuantum(opts); err != nil {
+			return nil, err
+		}
+		if err := scheduler.CheckSwitchCost(cpus, opts.Quantum); err != nil {
+			return nil, err
+		}
+		return New(cpus, opts.Quantum, opts.Seed), nil
+	})
+}
+
+var _ scheduler.Scheduler = (*lottery)(nil)
+
+// New returns a lottery scheduler drawing from a random number generator
+// with the given seed; schedules are reproducible for a fixed seed.
+// New panics if a CPU's context switch cost is not less than the quantum.
+func New(cpus []*cpu.CPU, quantum time.Duration, seed int64) *lottery {
+	if len(cpus) == 0 {
+		panic("lottery scheduler requires at least one CPU")
+	}
+	if err := scheduler.CheckSwitchCost(cpus, quantum); err != nil {
+		panic("lottery scheduler: " + err.Error())
+	}
+	return &lottery{
+		cpus:     cpus,
+		queue:    make(job.Jobs, 0),
+		quantum:  quantum,
+		rng:      rand.New(rand.NewSource(seed)),
+		currency: make(map[*job.Job]*Currency),
+	}
+}
+
+// Add adds the job to the pool of runnable jobs.
+// A job without tickets is given DefaultTickets base tickets.
+func (l *lottery) Add(job *job.Job) {
+	if job.Tickets <= 0 {
+		job.Tickets = DefaultTickets
+	}
+	l.queue = append(l.queue, job)
+}
+
+// SetCurrency denominates the job's tickets in the given currency.
+// A nil currency denominates the job's tickets in base tickets.
+func (l *lottery) SetCurrency(job *job.Job, c *Currency) {
+	if c == nil {
+		delete(l.currency, job)
+		return
+	}
+	l.currency[job] = c
+}
+
+// Transfer moves tickets from one job to another, e.g. from a client
+// blocked waiting for a server to the server doing the work on its behalf.
+// Both jobs must hold tickets in the same currency, so that the tickets
+// keep their value; the value of a ticket differs between currencies.
+func (l *lottery) Transfer(from, to *job.Job, tickets int) error {
+	if tickets <= 0 {
+		return errInvalidTickets
+	}
+	if l.currency[from] != l.currency[to] {
+		return errCurrencyMismatch
+	}
+	if from.Tickets < tickets {
+		return errNotEnoughTickets
+	}
+	from.Tickets -= tickets
+	to.Tickets += tickets
+	return nil
+}
+
+// Inflate issues the given number of extra tickets to the job, or
+// withdraws tickets if negative. Inflating a job in a currency only
+// deflates the other tickets of that currency; jobs in other currencies
+// keep their share. Base tickets are not isolated: inflating a job holding
+// base tickets reduces the share of every other job, in any currency.
+func (l *lottery) Inflate(job *job.Job, tickets int) {
+	job.Tickets += tickets
+	if job.Tickets < 0 {
+		job.Tickets = 0
+	}
+}
+
+// value returns the value of the job's tickets in base tickets.
+// Only the runnable jobs count towards the tickets issued in a currency.
+func (l *lottery) value(j *job.Job, runnable job.Jobs) float64 {
+	c, ok := l.currency[j]
+	if !ok {
+		return float64(j.Tickets)
+	}
+	issued := c.issued(runnable, l.currency)
+	if issued == 0 {
+		return 0
+	}
+	return float64(c.funding) * float64(j.Tickets) / float64(issued)
+}
+
+// Tick runs the scheduled jobs for the system time, and returns
+// the number of jobs finished in this tick. Depending on scheduler requirements,
+// the Tick method may assign new jobs to the CPU before returning.
+func (l *lottery) Tick(systemTime time.Duration) int {
+	jobsFinished := 0
+	sliceExhausted := systemTime%l.quantum == 0
+	preempted := make([]bool, len(l.cpus))
+	for i, c := range l.cpus {
+		if current := c.CurrentJob(); current != nil && c.Tick() {
+			jobsFinished++
+			delete(l.currency, current)
+		}
+		if sliceExhausted && c.IsRunning() {
+			// running jobs take part in the next draw
+			l.queue = append(l.queue, c.CurrentJob())
+			preempted[i] = true
+		}
+	}
+	for i, c := range l.cpus {
+		if preempted[i] || !c.IsRunning() {
+			c.Assign(l.draw())
+		}
+	}
+	return jobsFinished
+}
+
+// draw removes and returns the holder of a randomly drawn winning ticket,
+// or nil if there are no runnable jobs. If no runnable job holds tickets
+// of any value, the first job in the queue wins.
+func (l *lottery) draw() *job.Job {
+	if len(l.queue) == 0 {
+		return nil
+	}
+	runnable := append(job.Jobs{}, l.queue...)
+	for _, j := range l.Running() {
+		// preempted jobs are both running and queued until reassigned
+		if !l.queue.Has(j) {
+			runnable = append(runnable, j)
+		}
+	}
+	values := make([]float64, len(l.queue))
+	total := 0.0
+	for i, j := range l.queue {
+		values[i] = l.value(j, runnable)
+		total += values[i]
+	}
+	winner := 0
+	if total > 0 {
+		ticket := l.rng.Float64() * total
+		counter := 0.0
+		winner = len(values) - 1
+		for i := range values {
+			counter += values[i]
+			if counter > ticket {
+				winner = i
+				break
+			}
+		}
+	}
+	winningJob := l.queue[winner]
+	l.queue = append(l.queue[:winner], l.queue[winner+1:]...)
+	return winningJob
+}
+
+// Len returns the number of jobs waiting for the next draw.
+func (l *lottery) Len() int {
+	return len(l.queue)
+}
+
+// Running returns the jobs currently running on the CPUs.
+func (l *lottery) Running() job.Jobs {
+	return cpu.Running(l.cpus)
+}
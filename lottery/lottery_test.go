@@ -0,0 +1,119 @@
+package lottery
+
+import (
+	"dat320/lab4/scheduler/cpu"
+	"dat320/lab4/scheduler/job"
+	"dat320/lab4/scheduler/system/systime"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const ms = time.Millisecond
+
+// runFor runs the jobs on the scheduler for the given number of ticks,
+// and returns the index of the job running on the first CPU after each tick.
+func runFor(l *lottery, jobs job.Jobs, ticks int) []int {
+	clk := &systime.ManualClock{}
+	for _, j := range jobs {
+		j.Scheduled(clk)
+		l.Add(j)
+	}
+	index := make(map[*job.Job]int)
+	for i, j := range jobs {
+		index[j] = i
+	}
+	schedule := make([]int, 0, ticks)
+	for ; clk.Time < time.Duration(ticks)*ms; clk.Time += ms {
+		l.Tick(clk.Time)
+		if current := l.cpus[0].CurrentJob(); current != nil {
+			schedule = append(schedule, index[current])
+		}
+	}
+	return schedule
+}
+
+func newJobs(tickets ...int) job.Jobs {
+	jobs := make(job.Jobs, len(tickets))
+	for i, t := range tickets {
+		jobs[i] = job.New(0, time.Second)
+		jobs[i].Tickets = t
+	}
+	return jobs
+}
+
+func TestLotteryDeterministic(t *testing.T) {
+	first := runFor(New(cpu.NewCPUs(1), ms, 42), newJobs(50, 50, 100), 50)
+	second := runFor(New(cpu.NewCPUs(1), ms, 42), newJobs(50, 50, 100), 50)
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("same seed gave different schedules:\n%v\n%v", first, second)
+	}
+}
+
+func TestLotterySchedule(t *testing.T) {
+	// each draw holds the CPU for a 2ms quantum
+	got := runFor(New(cpu.NewCPUs(1), 2*ms, 42), newJobs(50, 50, 100), 20)
+	want := []int{1, 1, 0, 0, 1, 1, 2, 2, 0, 0, 2, 2, 2, 2, 0, 0, 2, 2, 2, 2}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("schedule = %v, want %v", got, want)
+	}
+}
+
+func TestLotteryProportionalShare(t *testing.T) {
+	const ticks = 1000
+	schedule := runFor(New(cpu.NewCPUs(1), ms, 1), newJobs(75, 25), ticks)
+	wins := 0
+	for _, i := range schedule {
+		if i == 0 {
+			wins++
+		}
+	}
+	if wins < 700 || wins > 800 {
+		t.Errorf("job with 75%% of tickets ran %d of %d ticks, want about 750", wins, ticks)
+	}
+}
+
+func TestLotteryCurrency(t *testing.T) {
+	// alice's currency is worth 100 base tickets, shared by two jobs;
+	// bob holds 100 base tickets, and should get as much as alice's jobs combined
+	l := New(cpu.NewCPUs(1), ms, 7)
+	alice := NewCurrency("alice", 100)
+	jobs := newJobs(500, 1500, 100)
+	l.SetCurrency(jobs[0], alice)
+	l.SetCurrency(jobs[1], alice)
+	wantValues := []float64{25, 75, 100}
+	for i, j := range jobs {
+		if got := l.value(j, jobs); got != wantValues[i] {
+			t.Errorf("value(%s) = %v, want %v", j.Name(), got, wantValues[i])
+		}
+	}
+	// inflation within alice's currency does not affect bob
+	l.Inflate(jobs[0], 1000)
+	wantValues = []float64{50, 50, 100}
+	for i, j := range jobs {
+		if got := l.value(j, jobs); got != wantValues[i] {
+			t.Errorf("after Inflate: value(%s) = %v, want %v", j.Name(), got, wantValues[i])
+		}
+	}
+}
+
+func TestLotteryTransfer(t *testing.T) {
+	l := New(cpu.NewCPUs(1), ms, 1)
+	jobs := newJobs(100, 10)
+	if err := l.Transfer(jobs[0], jobs[1], 60); err != nil {
+		t.Fatalf("Transfer(60) = %v, want nil", err)
+	}
+	if jobs[0].Tickets != 40 || jobs[1].Tickets != 70 {
+		t.Errorf("Transfer(60): tickets = (%d, %d), want (40, 70)", jobs[0].Tickets, jobs[1].Tickets)
+	}
+	if err := l.Transfer(jobs[0], jobs[1], 41); err != errNotEnoughTickets {
+		t.Errorf("Transfer(41) = %v, want %v", err, errNotEnoughTickets)
+	}
+	if err := l.Transfer(jobs[0], jobs[1], 0); err != errInvalidTickets {
+		t.Errorf("Transfer(0) = %v, want %v", err, errInvalidTickets)
+	}
+	l.SetCurrency(jobs[1], NewCurrency("alice", 100))
+	if err := l.Transfer(jobs[0], jobs[1], 10); err != errCurrencyMismatch {
+		t.Errorf("Transfer(10) to another currency = %v, want %v", err, errCurrencyMismatch)
+	}
+}
@@ -0,0 +1,199 @@
+// Package metrics summarizes the finished jobs of a scheduling simulation.
+package metrics
+
+import (
+	"dat320/lab4/scheduler/cpu"
+	"dat320/lab4/scheduler/job"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+var errNameMismatch = errors.New("need one name per report")
+
+// Stats summarizes a distribution of durations.
+// Durations are encoded in JSON as nanoseconds.
+type Stats struct {
+	Mean   time.Duration `json:"mean"`
+	Median time.Duration `json:"median"`
+	P95    time.Duration `json:"p95"`
+	Max    time.Duration `json:"max"`
+}
+
+// Report summarizes a simulation run.
+type Report struct {
+	Jobs        int           `json:"jobs"`
+	Makespan    time.Duration `json:"makespan"`   // first arrival to last completion
+	Throughput  float64       `json:"throughput"` // jobs finished per second
+	Turnaround  Stats         `json:"turnaround"`
+	Response    Stats         `json:"response"`
+	Waiting     Stats         `json:"waiting"`     // time spent in run queues
+	Utilization []float64     `json:"utilization"` // fraction of the makespan each CPU was busy
+	Fairness    float64       `json:"fairness"`    // Jain's fairness index of the jobs' progress rates
+	Preemptions int           `json:"preemptions"` // total number of times jobs were preempted
+	Overhead    []float64     `json:"overhead"`    // fraction of the makespan each CPU spent context switching
+}
+
+// Summarize returns a report of the finished jobs and the CPUs they ran on.
+func Summarize(jobs job.Jobs, cpus []*cpu.CPU) Report {
+	r := Report{Jobs: len(jobs), Utilization: make([]float64, len(cpus)), Overhead: make([]float64, len(cpus))}
+	if len(jobs) == 0 {
+		return r
+	}
+	turnaround := make([]time.Duration, len(jobs))
+	response := make([]time.Duration, len(jobs))
+	waiting := make([]time.Duration, len(jobs))
+	rates := make([]float64, len(jobs))
+	first, last := jobs[0].Arrival(), jobs[0].Finished()
+	for i, j := range jobs {
+		turnaround[i] = j.TurnaroundTime()
+		response[i] = j.ResponseTime()
+		waiting[i] = j.WaitingTime()
+		r.Preemptions += j.ContextSwitches()
+		if turnaround[i] > 0 {
+			rates[i] = float64(j.Estimated()) / float64(turnaround[i])
+		}
+		if j.Arrival() < first {
+			first = j.Arrival()
+		}
+		if j.Finished() > last {
+			last = j.Finished()
+		}
+	}
+	r.Turnaround = summarize(turnaround)
+	r.Response = summarize(response)
+	r.Waiting = summarize(waiting)
+	r.Fairness = JainIndex(rates)
+	r.Makespan = last - first
+	if r.Makespan > 0 {
+		r.Throughput = float64(len(jobs)) / r.Makespan.Seconds()
+		for i, c := range cpus {
+			r.Utilization[i] = float64(c.BusyTime()) / float64(r.Makespan)
+			r.Overhead[i] = float64(c.SwitchTime()) / float64(r.Makespan)
+		}
+	}
+	return r
+}
+
+// summarize returns the statistics of the given durations.
+func summarize(durations []time.Duration) Stats {
+	sorted := make([]time.Duration, len(durations))
+	copy(sorted, durations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	var sum time.Duration
+	for _, d := range sorted {
+		sum += d
+	}
+	return Stats{
+		Mean:   sum / time.Duration(len(sorted)),
+		Median: percentile(sorted, 50),
+		P95:    percentile(sorted, 95),
+		Max:    sorted[len(sorted)-1],
+	}
+}
+
+// percentile returns the p-th percentile of the sorted durations,
+// using the nearest-rank method.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100 // ceil(p/100 * n)
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// JainIndex returns Jain's fairness index of the given values, which ranges
+// from 1/n when a single value dominates, to 1 when all values are equal.
+func JainIndex(values []float64) float64 {
+	var sum, sumSquares float64
+	for _, x := range values {
+		sum += x
+		sumSquares += x * x
+	}
+	if sumSquares == 0 {
+		return 0
+	}
+	return sum * sum / (float64(len(values)) * sumSquares)
+}
+
+// WriteTable writes the report as a human-readable table.
+func (r Report) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Jobs\t%d\n", r.Jobs)
+	fmt.Fprintf(tw, "Makespan\t%v\n", r.Makespan)
+	fmt.Fprintf(tw, "Throughput\t%.2f jobs/s\n", r.Throughput)
+	fmt.Fprintf(tw, "Fairness\t%.3f\n", r.Fairness)
+	fmt.Fprintf(tw, "Preemptions\t%d\n", r.Preemptions)
+	for i, u := range r.Utilization {
+		fmt.Fprintf(tw, "CPU%d utilization\t%.1f%% (%.1f%% switching)\n", i, 100*u, 100*r.Overhead[i])
+	}
+	fmt.Fprintln(tw, "\tMean\tMedian\tP95\tMax")
+	for _, row := range []struct {
+		name string
+		s    Stats
+	}{
+		{"Turnaround", r.Turnaround},
+		{"Response", r.Response},
+		{"Waiting", r.Waiting},
+	} {
+		fmt.Fprintf(tw, "%s\t%v\t%v\t%v\t%v\n", row.name, row.s.Mean, row.s.Median, row.s.P95, row.s.Max)
+	}
+	return tw.Flush()
+}
+
+// WriteComparison writes the reports side by side as a table, with one
+// column per report headed by the corresponding name.
+func WriteComparison(w io.Writer, names []string, reports []Report) error {
+	if len(names) != len(reports) {
+		return fmt.Errorf("%d names for %d reports: %w", len(names), len(reports), errNameMismatch)
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	row := func(label string, value func(Report) string) {
+		fmt.Fprintf(tw, "%s\t", label)
+		for _, r := range reports {
+			fmt.Fprintf(tw, "%s\t", value(r))
+		}
+		fmt.Fprintln(tw)
+	}
+	fmt.Fprint(tw, "\t")
+	for _, name := range names {
+		fmt.Fprintf(tw, "%s\t", name)
+	}
+	fmt.Fprintln(tw)
+	row("Jobs", func(r Report) string { return fmt.Sprint(r.Jobs) })
+	row("Makespan", func(r Report) string { return r.Makespan.String() })
+	row("Throughput (jobs/s)", func(r Report) string { return fmt.Sprintf("%.2f", r.Throughput) })
+	row("Fairness", func(r Report) string { return fmt.Sprintf("%.3f", r.Fairness) })
+	row("Preemptions", func(r Report) string { return fmt.Sprint(r.Preemptions) })
+	row("Utilization", func(r Report) string { return fmt.Sprintf("%.1f%%", 100*mean(r.Utilization)) })
+	row("Turnaround mean", func(r Report) string { return r.Turnaround.Mean.String() })
+	row("Turnaround p95", func(r Report) string { return r.Turnaround.P95.String() })
+	row("Response mean", func(r Report) string { return r.Response.Mean.String() })
+	row("Response p95", func(r Report) string { return r.Response.P95.String() })
+	row("Waiting mean", func(r Report) string { return r.Waiting.Mean.String() })
+	row("Waiting p95", func(r Report) string { return r.Waiting.P95.String() })
+	return tw.Flush()
+}
+
+// mean returns the mean of the values, or 0 if there are none.
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, x := range values {
+		sum += x
+	}
+	return sum / float64(len(values))
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
@@ -0,0 +1,96 @@
+package metrics
+
+import (
+	"bytes"
+	"dat320/lab4/scheduler/cpu"
+	"dat320/lab4/scheduler/fifo"
+	"dat320/lab4/scheduler/job"
+	"dat320/lab4/scheduler/system"
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const ms = time.Millisecond
+
+func TestSummarize(t *testing.T) {
+	cpus := cpu.NewCPUs(1)
+	schedule := system.Schedule{
+		{Job: job.New(0, 3*ms), Arrival: 0},
+		{Job: job.New(0, 2*ms), Arrival: 0},
+		{Job: job.New(0, 1*ms), Arrival: 0},
+	}
+	jobs, err := system.Run(fifo.New(cpus), schedule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := Summarize(jobs, cpus)
+	// turnaround 3, 5 and 6ms; response and waiting 0, 3 and 5ms
+	wantTurnaround := Stats{Mean: 14 * ms / 3, Median: 5 * ms, P95: 6 * ms, Max: 6 * ms}
+	wantResponse := Stats{Mean: 8 * ms / 3, Median: 3 * ms, P95: 5 * ms, Max: 5 * ms}
+	if r.Turnaround != wantTurnaround {
+		t.Errorf("Turnaround = %+v, want %+v", r.Turnaround, wantTurnaround)
+	}
+	if r.Response != wantResponse || r.Waiting != wantResponse {
+		t.Errorf("Response = %+v, Waiting = %+v, want %+v", r.Response, r.Waiting, wantResponse)
+	}
+	if r.Makespan != 6*ms || r.Throughput != 500 {
+		t.Errorf("Makespan = %v, Throughput = %v, want 6ms and 500 jobs/s", r.Makespan, r.Throughput)
+	}
+	if !reflect.DeepEqual(r.Utilization, []float64{1}) {
+		t.Errorf("Utilization = %v, want [1]", r.Utilization)
+	}
+	if math.Abs(r.Fairness-0.6888) > 0.001 {
+		t.Errorf("Fairness = %.4f, want 0.6888", r.Fairness)
+	}
+
+	var table, js bytes.Buffer
+	if err := r.WriteTable(&table); err != nil || !strings.Contains(table.String(), "Turnaround") {
+		t.Errorf("WriteTable() = %q, %v", table.String(), err)
+	}
+	if err := r.WriteJSON(&js); err != nil {
+		t.Fatal(err)
+	}
+	var decoded Report
+	if err := json.Unmarshal(js.Bytes(), &decoded); err != nil || !reflect.DeepEqual(decoded, r) {
+		t.Errorf("WriteJSON() round trip = %+v, %v; want %+v", decoded, err, r)
+	}
+}
+
+func TestJainIndex(t *testing.T) {
+	tests := []struct {
+		values []float64
+		want   float64
+	}{
+		{[]float64{1, 1, 1, 1}, 1},
+		{[]float64{1, 0, 0, 0}, 0.25},
+		{[]float64{2, 1}, 0.9},
+		{[]float64{0, 0}, 0},
+	}
+	for _, test := range tests {
+		if got := JainIndex(test.values); math.Abs(got-test.want) > 1e-9 {
+			t.Errorf("JainIndex(%v) = %v, want %v", test.values, got, test.want)
+		}
+	}
+}
+
+func TestWriteComparison(t *testing.T) {
+	reports := []Report{{Jobs: 3, Makespan: 6 * ms}, {Jobs: 3, Makespan: 8 * ms}}
+	var buf bytes.Buffer
+	if err := WriteComparison(&buf, []string{"fifo", "rr"}, reports); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(buf.String(), "\n")
+	if fields := strings.Fields(lines[0]); !reflect.DeepEqual(fields, []string{"fifo", "rr"}) {
+		t.Errorf("WriteComparison() header = %q, want fifo and rr", lines[0])
+	}
+	if fields := strings.Fields(lines[2]); !reflect.DeepEqual(fields, []string{"Makespan", "6ms", "8ms"}) {
+		t.Errorf("WriteComparison() makespan row = %q", lines[2])
+	}
+	if err := WriteComparison(&buf, []string{"fifo"}, reports); err == nil {
+		t.Errorf("WriteComparison() with missing name: got nil error")
+	}
+}
@@ -0,0 +1,309 @@
+// Package mlfq implements the Multi-Level Feedback Queue scheduler described
+// in OSTEP chapter 8:
+//
+//	Rule 1: If Priority(A) > Priority(B), A runs (B doesn't).
+//	Rule 2: If Priority(A) = Priority(B), A & B run in round-robin fashion
+//	        using the time slice (quantum length) of the given queue.
+//	Rule 3: When a job enters the system, it is placed at the highest priority.
+//	Rule 4: Once a job uses up its time allotment at a given level (regardless
+//	        of how many times it has given up the CPU), its priority is reduced.
+//	Rule 5: After some time period S, move all the jobs in the system to the
+//	        topmost queue.
+//
+// Level 0 is the highest priority.
+package mlfq
+
+import (
+	"dat320/lab4/scheduler"
+	"dat320/lab4/scheduler/cpu"
+	"dat320/lab4/scheduler/job"
+	"dat320/lab4/scheduler/system/systime"
+	"errors"
+	"time"
+)
+
+var (
+	errNoLevels       = errors.New("invalid argument: at least one priority level is required")
+	errLevelMismatch  = errors.New("invalid argument: need one quantum and one allotment per level")
+	errInvalidQuantum = errors.New("invalid argument: quantum and allotment must be greater than 0")
+)
+
+// Config holds the parameters of the MLFQ scheduler.
+type Config struct {
+	Quanta      []time.Duration // time slice of each level
+	Allotments  []time.Duration // time a job may use at each level before demotion
+	BoostPeriod time.Duration   // interval between priority boosts; zero disables boosting
+}
+
+// DefaultConfig returns a configuration with the given number of levels,
+// where the quantum doubles for each lower level and the allotment at
+// each level is equal to its quantum. If levels is less than 1, the
+// configuration has no levels, and New rejects it.
+func DefaultConfig(levels int, quantum, boostPeriod time.Duration) Config {
+	if levels < 1 {
+		return Config{BoostPeriod: boostPeriod}
+	}
+	cfg := Config{
+		Quanta:      make([]time.Duration, levels),
+		Allotments:  make([]time.Duration, levels),
+		BoostPeriod: boostPeriod,
+	}
+	for i := range cfg.Quanta {
+		cfg.Quanta[i] = quantum << i
+		cfg.Allotments[i] = quantum << i
+	}
+	return cfg
+}
+
+func (cfg Config) validate() error {
+	if len(cfg.Quanta) == 0 {
+		return errNoLevels
+	}
+	if len(cfg.Quanta) != len(cfg.Allotments) {
+		return errLevelMismatch
+	}
+	for i := range cfg.Quanta {
+		if cfg.Quanta[i] <= 0 || cfg.Allotments[i] <= 0 {
+			return errInvalidQuantum
+		}
+	}
+	return nil
+}
+
+// minSlice returns the shortest time a job may run before it is preempted,
+// that is, the smallest quantum or allotment of any level.
+func (cfg Config) minSlice() time.Duration {
+	shortest := cfg.Quanta[0]
+	for i := range cfg.Quanta {
+		if cfg.Quanta[i] < shortest {
+			shortest = cfg.Quanta[i]
+		}
+		if cfg.Allotments[i] < shortest {
+			shortest = cfg.Allotments[i]
+		}
+	}
+	return shortest
+}
+
+const defaultLevels = 3
+
+func init() {
+	scheduler.Register("mlfq", func(cpus []*cpu.CPU, opts scheduler.Options) (scheduler.Scheduler, error) {
+		if err := scheduler.RequireQuantum(opts); err != nil {
+			return nil, err
+		}
+		levels := opts.Levels
+		if levels == 0 {
+			levels = defaultLevels
+		}
+		if levels < 1 {
+			return nil, errNoLevels
+		}
+		return New(cpus, DefaultConfig(levels, opts.Quantum, opts.BoostPeriod))
+	})
+}
+
+var (
+	_ scheduler.Scheduler = (*mlfq)(nil)
+	_ scheduler.Blocker   = (*mlfq)(nil)
+)
+
+// jobState is the scheduler's bookkeeping for a job in the system.
+type jobState struct {
+	level int           // current priority level
+	used  time.Duration // time used of the allotment at the current level
+	slice time.Duration // time used of the current quantum
+}
+
+type mlfq struct {
+	cpus   []*cpu.CPU
+	levels []job.Jobs // run queue for each priority level
+	config Config
+	state  map[*job.Job]*jobState
+}
+
+// New returns a new MLFQ scheduler for the given CPUs. It returns an error
+// if a CPU's context switch cost is not less than the smallest quantum or
+// allotment of any level.
+func New(cpus []*cpu.CPU, config Config) (*mlfq, error) {
+	if len(cpus) == 0 {
+		panic("mlfq scheduler requires at least one CPU")
+	}
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	if err := scheduler.CheckSwitchCost(cpus, config.minSlice()); err != nil {
+		return nil, err
+	}
+	return &mlfq{
+		cpus:   cpus,
+		levels: make([]job.Jobs, len(config.Quanta)),
+		config: config,
+		state:  make(map[*job.Job]*jobState),
+	}, nil
+}
+
+// Add places a new job at the highest priority (Rule 3).
+func (m *mlfq) Add(job *job.Job) {
+	m.state[job] = &jobState{}
+	m.enqueue(job)
+}
+
+// Block resets the quantum of a job that gave up the CPU to wait for I/O.
+// The job keeps its priority level and the allotment it has used, so that
+// it cannot game the scheduler by blocking just before its quantum expires (Rule 4).
+func (m *mlfq) Block(job *job.Job) {
+	m.state[job].slice = 0
+}
+
+// Unblock puts a job whose I/O completed back in the queue of its priority level.
+func (m *mlfq) Unblock(job *job.Job) {
+	m.enqueue(job)
+}
+
+// enqueue adds the job to the back of the queue of its current level.
+func (m *mlfq) enqueue(job *job.Job) {
+	level := m.state[job].level
+	m.levels[level] = append(m.levels[level], job)
+}
+
+// Tick runs the scheduled jobs for the system time, and returns
+// the number of jobs finished in this tick. Depending on scheduler requirements,
+// the Tick method may assign new jobs to the CPU before returning.
+func (m *mlfq) Tick(systemTime time.Duration) int {
+	jobsFinished := 0
+	expired := make([]bool, len(m.cpus))
+	for i, c := range m.cpus {
+		if !c.IsRunning() {
+			continue
+		}
+		current := c.CurrentJob()
+		if c.Tick() {
+			jobsFinished++
+			delete(m.state, current)
+			continue
+		}
+		st := m.state[current]
+		st.used += systime.TickDuration
+		st.slice += systime.TickDuration
+		switch {
+		case st.used >= m.config.Allotments[st.level]:
+			// Rule 4: allotment used up, even if the job just blocked; demote the job
+			if st.level < len(m.levels)-1 {
+				st.level++
+			}
+			st.used, st.slice = 0, 0
+			expired[i] = true
+		case st.slice >= m.config.Quanta[st.level]:
+			// Rule 2: quantum used up; round-robin within the level
+			st.slice = 0
+			expired[i] = true
+		}
+	}
+	if m.config.BoostPeriod > 0 && systemTime > 0 && systemTime%m.config.BoostPeriod == 0 {
+		m.boost()
+	}
+	preempted := make([]bool, len(m.cpus))
+	for i, c := range m.cpus {
+		// a job that blocked is no longer running, and is not put back in a queue
+		if expired[i] && c.IsRunning() {
+			m.enqueue(c.CurrentJob())
+			preempted[i] = true
+		}
+	}
+	for i, c := range m.cpus {
+		if preempted[i] || !c.IsRunning() {
+			c.Assign(m.getNewJob())
+		}
+	}
+	m.preempt()
+	return jobsFinished
+}
+
+// boost moves all jobs in the system to the topmost queue (Rule 5).
+func (m *mlfq) boost() {
+	top := make(job.Jobs, 0)
+	for level := range m.levels {
+		top = append(top, m.levels[level]...)
+		m.levels[level] = nil
+	}
+	m.levels[0] = top
+	for _, st := range m.state {
+		st.level, st.used, st.slice = 0, 0, 0
+	}
+}
+
+// preempt replaces running jobs with queued jobs of higher priority (Rule 1).
+func (m *mlfq) preempt() {
+	for {
+		level := m.highestLevel()
+		lowest := m.lowestRunning()
+		if level < 0 || lowest == nil || level >= m.state[lowest.CurrentJob()].level {
+			return
+		}
+		st := m.state[lowest.CurrentJob()]
+		st.slice = 0
+		m.enqueue(lowest.CurrentJob())
+		lowest.Assign(m.getNewJob())
+	}
+}
+
+// lowestRunning returns the CPU running the job with the lowest priority,
+// or nil if all CPUs are idle.
+func (m *mlfq) lowestRunning() *cpu.CPU {
+	var lowest *cpu.CPU
+	for _, c := range m.cpus {
+		if !c.IsRunning() {
+			continue
+		}
+		if lowest == nil || m.state[c.CurrentJob()].level > m.state[lowest.CurrentJob()].level {
+			lowest = c
+		}
+	}
+	return lowest
+}
+
+// highestLevel returns the highest priority level with a waiting job, or -1.
+func (m *mlfq) highestLevel() int {
+	for level, queue := range m.levels {
+		if len(queue) > 0 {
+			return level
+		}
+	}
+	return -1
+}
+
+// getNewJob removes and returns the first job of the highest priority level
+// with waiting jobs, or nil if all queues are empty.
+func (m *mlfq) getNewJob() *job.Job {
+	level := m.highestLevel()
+	if level < 0 {
+		return nil
+	}
+	removedJob := m.levels[level][0]
+	m.levels[level] = m.levels[level][1:]
+	return removedJob
+}
+
+// Level returns the current priority level of the job,
+// or -1 if the job is not in the system.
+func (m *mlfq) Level(job *job.Job) int {
+	if st, ok := m.state[job]; ok {
+		return st.level
+	}
+	return -1
+}
+
+// Len returns the number of jobs waiting in all queues.
+func (m *mlfq) Len() int {
+	n := 0
+	for _, queue := range m.levels {
+		n += len(queue)
+	}
+	return n
+}
+
+// Running returns the jobs currently running on the CPUs.
+func (m *mlfq) Running() job.Jobs {
+	return cpu.Running(m.cpus)
+}
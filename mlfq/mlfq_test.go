@@ -0,0 +1,114 @@
+package mlfq
+
+import (
+	"dat320/lab4/scheduler"
+	"dat320/lab4/scheduler/cpu"
+	"dat320/lab4/scheduler/job"
+	"dat320/lab4/scheduler/system"
+	"dat320/lab4/scheduler/system/systime"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const ms = time.Millisecond
+
+func TestMLFQ(t *testing.T) {
+	clk := &systime.ManualClock{}
+	m, err := New(cpu.NewCPUs(1), DefaultConfig(3, ms, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// B arrives while A is at level 1 and preempts it (Rule 1)
+	jobs := job.Jobs{job.New(0, 6*ms), job.New(0, 2*ms)}
+	arrivals := []time.Duration{0, 2 * ms}
+	for finished := 0; finished < len(jobs); clk.Time += ms {
+		for i, j := range jobs {
+			if arrivals[i] == clk.Time {
+				j.Scheduled(clk)
+				m.Add(j)
+			}
+		}
+		finished += m.Tick(clk.Time)
+	}
+	wantTurnaround := []time.Duration{8 * ms, 3 * ms}
+	wantResponse := []time.Duration{0, 0}
+	for i, j := range jobs {
+		if got := j.TurnaroundTime(); got != wantTurnaround[i] {
+			t.Errorf("job %s: TurnaroundTime() = %v, want %v", j.Name(), got, wantTurnaround[i])
+		}
+		if got := j.ResponseTime(); got != wantResponse[i] {
+			t.Errorf("job %s: ResponseTime() = %v, want %v", j.Name(), got, wantResponse[i])
+		}
+	}
+}
+
+func TestMLFQBoost(t *testing.T) {
+	clk := &systime.ManualClock{}
+	m, err := New(cpu.NewCPUs(1), DefaultConfig(3, ms, 5*ms))
+	if err != nil {
+		t.Fatal(err)
+	}
+	j := job.New(0, 10*ms)
+	j.Scheduled(clk)
+	m.Add(j)
+	var levels []int
+	for ; clk.Time <= 6*ms; clk.Time += ms {
+		m.Tick(clk.Time)
+		levels = append(levels, m.Level(j))
+	}
+	if want := []int{0, 1, 1, 2, 2, 0, 1}; !reflect.DeepEqual(levels, want) {
+		t.Errorf("levels = %v, want %v", levels, want)
+	}
+}
+
+func TestNewInvalidConfig(t *testing.T) {
+	configs := []Config{
+		{},
+		{Quanta: []time.Duration{ms}},
+		{Quanta: []time.Duration{ms}, Allotments: []time.Duration{0}},
+		DefaultConfig(0, ms, 0),
+		DefaultConfig(-1, ms, 0),
+	}
+	for _, cfg := range configs {
+		if _, err := New(cpu.NewCPUs(1), cfg); err == nil {
+			t.Errorf("New(%+v) = nil error, want error", cfg)
+		}
+	}
+	opts := scheduler.Options{Quantum: ms, Levels: -1}
+	if _, err := scheduler.New("mlfq", cpu.NewCPUs(1), opts); !errors.Is(err, errNoLevels) {
+		t.Errorf("scheduler.New(mlfq, %+v) error = %v, want %v", opts, err, errNoLevels)
+	}
+}
+
+func TestMLFQBlockKeepsLevel(t *testing.T) {
+	m, err := New(cpu.NewCPUs(1), DefaultConfig(3, 2*ms, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the interactive job uses up its allotment across several bursts,
+	// and must not regain top priority by blocking for I/O
+	interactive := job.NewIO(0, 1*ms, 2*ms, 1*ms, 2*ms, 1*ms, 2*ms, 1*ms)
+	sys := system.New(m, system.Schedule{
+		{Job: interactive, Arrival: 0},
+		{Job: job.New(0, 20*ms), Arrival: 0},
+	})
+	var levels []int
+	sys.OnTick(func(time.Duration) {
+		if level := m.Level(interactive); level >= 0 {
+			levels = append(levels, level)
+		}
+	})
+	if _, err := sys.Run(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i < len(levels); i++ {
+		if levels[i] < levels[i-1] {
+			t.Fatalf("level went from %d to %d without a boost: %v", levels[i-1], levels[i], levels)
+		}
+	}
+	if levels[len(levels)-1] == 0 {
+		t.Errorf("interactive job stayed at level 0 after using %v of CPU: %v", interactive.RunTime(), levels)
+	}
+}
@@ -0,0 +1,101 @@
+// Package observe provides sinks for job lifecycle events: an in-memory
+// recorder, a JSON-lines log and a channel. A sink is subscribed to the
+// jobs of a simulation with system.System.Observe:
+//
+//	rec := observe.NewRecorder()
+//	sys := system.New(sched, schedule)
+//	sys.Observe(rec)
+//	sys.Run()
+//	for _, e := range rec.Filter(job.Preempted) { ... }
+package observe
+
+import (
+	"dat320/lab4/scheduler/job"
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+var (
+	_ job.Observer = (*Recorder)(nil)
+	_ job.Observer = (*JSONLines)(nil)
+	_ job.Observer = Channel(nil)
+)
+
+// Recorder records events in memory. It is safe for concurrent use.
+type Recorder struct {
+	mu     sync.Mutex
+	events []job.Event
+}
+
+// NewRecorder returns an empty recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Observe records the event.
+func (r *Recorder) Observe(e job.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, e)
+}
+
+// Events returns the recorded events in the order they were observed.
+func (r *Recorder) Events() []job.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]job.Event(nil), r.events...)
+}
+
+// Filter returns the recorded events of the given kinds.
+func (r *Recorder) Filter(kinds ...job.Kind) []job.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var events []job.Event
+	for _, e := range r.events {
+		for _, k := range kinds {
+			if e.Kind == k {
+				events = append(events, e)
+				break
+			}
+		}
+	}
+	return events
+}
+
+// JSONLines writes each event as a line of JSON. It is safe for concurrent use.
+type JSONLines struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+	err error
+}
+
+// NewJSONLines returns a sink writing events to w.
+func NewJSONLines(w io.Writer) *JSONLines {
+	return &JSONLines{enc: json.NewEncoder(w)}
+}
+
+// Observe writes the event. After a write fails, further events are dropped.
+func (l *JSONLines) Observe(e job.Event) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.err == nil {
+		l.err = l.enc.Encode(e)
+	}
+}
+
+// Err returns the first error that occurred while writing events.
+func (l *JSONLines) Err() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.err
+}
+
+// Channel sends events on a channel. Sending blocks the simulation until
+// the event is received, unless the channel has room in its buffer.
+type Channel chan<- job.Event
+
+// Observe sends the event on the channel.
+func (c Channel) Observe(e job.Event) {
+	c <- e
+}
@@ -0,0 +1,113 @@
+package observe
+
+import (
+	"bufio"
+	"bytes"
+	"dat320/lab4/scheduler/cpu"
+	"dat320/lab4/scheduler/job"
+	"dat320/lab4/scheduler/rr"
+	"dat320/lab4/scheduler/system"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const ms = time.Millisecond
+
+// simulate runs a CPU-bound job and a job doing I/O under round robin,
+// with the given observer subscribed to both jobs.
+func simulate(t *testing.T, o job.Observer) (cpuBound, ioBound *job.Job) {
+	t.Helper()
+	cpuBound, ioBound = job.New(0, 3*ms), job.NewIO(0, 1*ms, 2*ms, 1*ms)
+	sys := system.New(rr.New(cpu.NewCPUs(1), 2*ms), system.Schedule{
+		{Job: cpuBound, Arrival: 0},
+		{Job: ioBound, Arrival: 0},
+	})
+	sys.Observe(o)
+	if _, err := sys.Run(); err != nil {
+		t.Fatal(err)
+	}
+	return cpuBound, ioBound
+}
+
+func TestRecorder(t *testing.T) {
+	rec := NewRecorder()
+	cpuBound, ioBound := simulate(t, rec)
+	type event struct {
+		kind job.Kind
+		time time.Duration
+		cpu  int
+	}
+	want := map[int][]event{
+		cpuBound.ID(): {
+			{job.Arrived, 0, -1},
+			{job.Dispatched, 0, 0},
+			{job.Preempted, 2 * ms, 0},
+			{job.Dispatched, 3 * ms, 0},
+			{job.Finished, 4 * ms, 0},
+		},
+		ioBound.ID(): {
+			{job.Arrived, 0, -1},
+			{job.Dispatched, 2 * ms, 0},
+			{job.Blocked, 3 * ms, 0},
+			{job.Unblocked, 5 * ms, -1},
+			{job.Dispatched, 5 * ms, 0},
+			{job.Finished, 6 * ms, 0},
+		},
+	}
+	got := make(map[int][]event)
+	for _, e := range rec.Events() {
+		got[e.JobID] = append(got[e.JobID], event{e.Kind, e.Time, e.CPU})
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Events() =\n%v\nwant\n%v", got, want)
+	}
+	if n := len(rec.Filter(job.Finished, job.Blocked)); n != 3 {
+		t.Errorf("Filter(Finished, Blocked) returned %d events, want 3", n)
+	}
+}
+
+func TestJSONLines(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewJSONLines(&buf)
+	rec := NewRecorder()
+	simulate(t, job.ObserverFunc(func(e job.Event) {
+		log.Observe(e)
+		rec.Observe(e)
+	}))
+	if err := log.Err(); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []job.Event
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var e job.Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("line %q: %v", scanner.Text(), err)
+		}
+		decoded = append(decoded, e)
+	}
+	if !reflect.DeepEqual(decoded, rec.Events()) {
+		t.Errorf("JSON lines decode to %v, want %v", decoded, rec.Events())
+	}
+}
+
+func TestChannel(t *testing.T) {
+	ch := make(chan job.Event)
+	done := make(chan int)
+	go func() {
+		finished := 0
+		for e := range ch {
+			if e.Kind == job.Finished {
+				finished++
+			}
+		}
+		done <- finished
+	}()
+	simulate(t, Channel(ch))
+	close(ch)
+	if finished := <-done; finished != 2 {
+		t.Errorf("received %d finished events, want 2", finished)
+	}
+}
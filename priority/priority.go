@@ -0,0 +1,208 @@
+// Package priority implements priority scheduling with aging.
+// The job with the highest priority runs first, where a lower job.Job.Priority
+// value means a higher priority. To prevent starvation, the effective priority
+// of a waiting job is raised by one for every aging interval it has waited,
+// up to priority 0. A job keeps its effective priority while it runs, so that
+// an aged job is not immediately preempted again, and it falls back to its
+// static priority when it is preempted.
+package priority
+
+import (
+	"dat320/lab4/scheduler"
+	"dat320/lab4/scheduler/cpu"
+	"dat320/lab4/scheduler/job"
+	"sort"
+	"time"
+)
+
+// highest is the highest priority aging can raise a job to.
+const highest = 0
+
+// Config holds the parameters of the priority scheduler.
+type Config struct {
+	Preemptive bool          // preempt a running job when a waiting job has higher priority
+	Aging      time.Duration // waiting time per priority raise; zero disables aging
+}
+
+func init() {
+	scheduler.Register("priority", func(cpus []*cpu.CPU, opts scheduler.Options) (scheduler.Scheduler, error) {
+		return New(cpus, Config{Aging: opts.Aging}), nil
+	})
+	scheduler.Register("priority-preempt", func(cpus []*cpu.CPU, opts scheduler.Options) (scheduler.Scheduler, error) {
+		return New(cpus, Config{Preemptive: true, Aging: opts.Aging}), nil
+	})
+}
+
+var _ scheduler.Scheduler = (*priority)(nil)
+
+// Starvation holds the starvation statistics of a job.
+type Starvation struct {
+	JobID   int
+	Job     string        // name of the job
+	MaxWait time.Duration // longest time spent waiting without running
+	Agings  int           // number of times aging raised the job's priority
+}
+
+// jobState is the scheduler's bookkeeping for a job.
+type jobState struct {
+	effective int           // effective priority
+	queuedAt  time.Duration // when the job last entered the queue
+	stats     Starvation
+}
+
+type priority struct {
+	queue  job.Jobs
+	cpus   []*cpu.CPU
+	config Config
+	state  map[*job.Job]*jobState // kept after jobs finish, for their statistics
+}
+
+// New returns a priority scheduler for the given CPUs.
+func New(cpus []*cpu.CPU, config Config) *priority {
+	if len(cpus) == 0 {
+		panic("priority scheduler requires at least one CPU")
+	}
+	return &priority{
+		cpus:   cpus,
+		queue:  make(job.Jobs, 0),
+		config: config,
+		state:  make(map[*job.Job]*jobState),
+	}
+}
+
+// Add adds the job to the queue at its static priority. The job's
+// waiting time for aging is counted from the current system time.
+func (p *priority) Add(job *job.Job) {
+	st, ok := p.state[job]
+	if !ok {
+		st = &jobState{stats: Starvation{JobID: job.ID(), Job: job.Name()}}
+		p.state[job] = st
+	}
+	st.effective = job.Priority
+	if job.SystemTime != nil {
+		st.queuedAt = job.Now()
+	}
+	p.queue = append(p.queue, job)
+}
+
+// Tick runs the scheduled jobs for the system time, and returns
+// the number of jobs finished in this tick. Depending on scheduler requirements,
+// the Tick method may assign new jobs to the CPU before returning.
+func (p *priority) Tick(systemTime time.Duration) int {
+	jobsFinished := 0
+	for _, c := range p.cpus {
+		if c.IsRunning() && c.Tick() {
+			jobsFinished++
+		}
+	}
+	p.age(systemTime)
+	for _, c := range p.cpus {
+		if !c.IsRunning() {
+			p.reassign(c)
+		}
+	}
+	if p.config.Preemptive {
+		p.preempt(systemTime)
+	}
+	return jobsFinished
+}
+
+// age raises the effective priority of the waiting jobs according to how
+// long they have waited, and updates their starvation statistics.
+func (p *priority) age(systemTime time.Duration) {
+	for _, j := range p.queue {
+		st := p.state[j]
+		wait := systemTime - st.queuedAt
+		if wait > st.stats.MaxWait {
+			st.stats.MaxWait = wait
+		}
+		if p.config.Aging <= 0 {
+			continue
+		}
+		aged := j.Priority - int(wait/p.config.Aging)
+		if aged < highest {
+			aged = highest
+		}
+		if aged < st.effective {
+			st.stats.Agings += st.effective - aged
+			st.effective = aged
+		}
+	}
+}
+
+// preempt replaces the running job with the lowest priority by the waiting
+// job with the highest priority, for as long as the waiting job has higher priority.
+func (p *priority) preempt(systemTime time.Duration) {
+	for len(p.queue) > 0 {
+		best := p.queue[p.highest()]
+		lowest := p.lowestRunning()
+		if lowest == nil || p.state[best].effective >= p.state[lowest.CurrentJob()].effective {
+			return
+		}
+		preempted := lowest.CurrentJob()
+		p.reassign(lowest)
+		p.state[preempted].effective = preempted.Priority
+		p.state[preempted].queuedAt = systemTime
+		p.queue = append(p.queue, preempted)
+	}
+}
+
+// lowestRunning returns the CPU running the job with the lowest effective
+// priority, or nil if all CPUs are idle.
+func (p *priority) lowestRunning() *cpu.CPU {
+	var lowest *cpu.CPU
+	for _, c := range p.cpus {
+		if !c.IsRunning() {
+			continue
+		}
+		if lowest == nil || p.state[c.CurrentJob()].effective > p.state[lowest.CurrentJob()].effective {
+			lowest = c
+		}
+	}
+	return lowest
+}
+
+// reassign assigns the waiting job with the highest priority to the given CPU.
+func (p *priority) reassign(c *cpu.CPU) {
+	if len(p.queue) == 0 {
+		c.Assign(nil)
+		return
+	}
+	i := p.highest()
+	nxtJob := p.queue[i]
+	p.queue = append(p.queue[:i], p.queue[i+1:]...)
+	c.Assign(nxtJob)
+}
+
+// highest returns the index of the waiting job with the highest effective
+// priority; ties are broken in queue order.
+func (p *priority) highest() int {
+	highest := 0
+	for i := 1; i < len(p.queue); i++ {
+		if p.state[p.queue[i]].effective < p.state[p.queue[highest]].effective {
+			highest = i
+		}
+	}
+	return highest
+}
+
+// Starvation returns the starvation statistics of all jobs added
+// to the scheduler, ordered by job ID.
+func (p *priority) Starvation() []Starvation {
+	stats := make([]Starvation, 0, len(p.state))
+	for _, st := range p.state {
+		stats = append(stats, st.stats)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].JobID < stats[j].JobID })
+	return stats
+}
+
+// Len returns the number of jobs waiting in the queue.
+func (p *priority) Len() int {
+	return len(p.queue)
+}
+
+// Running returns the jobs currently running on the CPUs.
+func (p *priority) Running() job.Jobs {
+	return cpu.Running(p.cpus)
+}
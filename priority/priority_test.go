@@ -0,0 +1,89 @@
+package priority
+
+import (
+	"dat320/lab4/scheduler/cpu"
+	"dat320/lab4/scheduler/job"
+	"dat320/lab4/scheduler/system"
+	"testing"
+	"time"
+)
+
+const ms = time.Millisecond
+
+func newJob(size time.Duration, priority int) *job.Job {
+	j := job.New(0, size)
+	j.Priority = priority
+	return j
+}
+
+func TestPreemption(t *testing.T) {
+	tests := []struct {
+		name           string
+		preemptive     bool
+		wantTurnaround []time.Duration
+	}{
+		{"NonPreemptive", false, []time.Duration{5 * ms, 5 * ms}},
+		{"Preemptive", true, []time.Duration{6 * ms, 1 * ms}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			schedule := system.Schedule{
+				{Job: newJob(5*ms, 5), Arrival: 0},
+				{Job: newJob(1*ms, 1), Arrival: 1 * ms},
+			}
+			jobs, err := system.Run(New(cpu.NewCPUs(1), Config{Preemptive: test.preemptive}), schedule)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, j := range jobs {
+				if got := j.TurnaroundTime(); got != test.wantTurnaround[i] {
+					t.Errorf("job %s: TurnaroundTime() = %v, want %v", j.Name(), got, test.wantTurnaround[i])
+				}
+			}
+		})
+	}
+}
+
+// starvationSchedule returns a low priority job competing with a
+// steady stream of high priority jobs.
+func starvationSchedule() (*job.Job, system.Schedule) {
+	low := newJob(2*ms, 3)
+	schedule := system.Schedule{{Job: low, Arrival: 0}}
+	for i := 0; i < 10; i++ {
+		schedule = append(schedule, &system.Entry{Job: newJob(2*ms, 0), Arrival: time.Duration(2*i) * ms})
+	}
+	return low, schedule
+}
+
+func TestAging(t *testing.T) {
+	low, schedule := starvationSchedule()
+	if _, err := system.Run(New(cpu.NewCPUs(1), Config{Preemptive: true}), schedule); err != nil {
+		t.Fatal(err)
+	}
+	starved := low.Finished()
+	if want := 22 * ms; starved != want {
+		t.Errorf("without aging: low priority job finished at %v, want %v", starved, want)
+	}
+
+	low, schedule = starvationSchedule()
+	p := New(cpu.NewCPUs(1), Config{Preemptive: true, Aging: 2 * ms})
+	if _, err := system.Run(p, schedule); err != nil {
+		t.Fatal(err)
+	}
+	if got := low.Finished(); got >= starved {
+		t.Errorf("with aging: low priority job finished at %v, want before %v", got, starved)
+	}
+	for _, st := range p.Starvation() {
+		if st.JobID != low.ID() {
+			continue
+		}
+		if st.Agings != low.Priority {
+			t.Errorf("Starvation(): Agings = %d, want %d", st.Agings, low.Priority)
+		}
+		if want := 3 * 2 * ms; st.MaxWait < want {
+			t.Errorf("Starvation(): MaxWait = %v, want at least %v", st.MaxWait, want)
+		}
+		return
+	}
+	t.Errorf("Starvation(): no statistics for job %s", low.Name())
+}
@@ -0,0 +1,103 @@
+package scheduler
+
+import (
+	"dat320/lab4/scheduler/cpu"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	errUnknownPolicy = errors.New("unknown scheduling policy")
+	errNoCPUs        = errors.New("invalid argument: at least one CPU is required")
+	errNoQuantum     = errors.New("invalid argument: quantum must be greater than 0")
+	errSwitchCost    = errors.New("invalid argument: context switch cost must be less than the quantum")
+	errNegativeCost  = errors.New("invalid argument: context switch cost must not be negative")
+	errInvalidCache  = errors.New("invalid argument: cache size and warm-up time must not be negative, and warm speed must be at least 1")
+)
+
+var (
+	mu        sync.RWMutex
+	factories = make(map[string]Factory)
+)
+
+// Register makes a scheduling policy available by the given name.
+// Register panics if called twice with the same name or with a nil factory.
+func Register(name string, factory Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+	if factory == nil {
+		panic("scheduler: Register factory is nil for " + name)
+	}
+	if _, dup := factories[name]; dup {
+		panic("scheduler: Register called twice for " + name)
+	}
+	factories[name] = factory
+}
+
+// New returns a new scheduler for the named policy running on the given CPUs.
+// If opts specifies a context switch cost or a cache size, New sets the cost
+// on every CPU, or attaches a cache to every CPU with cpu.SetCaches,
+// before constructing the scheduler.
+func New(name string, cpus []*cpu.CPU, opts Options) (Scheduler, error) {
+	mu.RLock()
+	factory, ok := factories[name]
+	mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%q: %w", name, errUnknownPolicy)
+	}
+	if len(cpus) == 0 {
+		return nil, errNoCPUs
+	}
+	if opts.SwitchCost < 0 {
+		return nil, errNegativeCost
+	}
+	if opts.CacheSize < 0 || opts.CacheSize > 0 && (opts.CacheWarmup < 0 || opts.WarmSpeed < 1) {
+		return nil, errInvalidCache
+	}
+	if opts.SwitchCost > 0 {
+		for _, c := range cpus {
+			c.SetContextSwitchCost(opts.SwitchCost)
+		}
+	}
+	if opts.CacheSize > 0 {
+		cpu.SetCaches(cpus, opts.CacheSize, opts.CacheWarmup, opts.WarmSpeed)
+	}
+	return factory(cpus, opts)
+}
+
+// Names returns the sorted names of the registered policies.
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RequireQuantum returns an error if opts does not specify a positive quantum.
+// It is intended for use by factories of preemptive policies.
+func RequireQuantum(opts Options) error {
+	if opts.Quantum <= 0 {
+		return errNoQuantum
+	}
+	return nil
+}
+
+// CheckSwitchCost returns an error if the context switch cost of any of the
+// CPUs is not less than the quantum. A job dispatched at a quantum boundary
+// would then spend its whole time slice switching, and a policy preempting
+// jobs at every quantum would never make progress.
+func CheckSwitchCost(cpus []*cpu.CPU, quantum time.Duration) error {
+	for _, c := range cpus {
+		if cost := c.ContextSwitchCost(); cost > 0 && cost >= quantum {
+			return fmt.Errorf("%s: %v >= %v: %w", c.Header(), cost, quantum, errSwitchCost)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package scheduler_test
+
+import (
+	"dat320/lab4/scheduler"
+	"dat320/lab4/scheduler/cpu"
+	"dat320/lab4/scheduler/job"
+	"dat320/lab4/scheduler/system"
+	"reflect"
+	"testing"
+	"time"
+
+	_ "dat320/lab4/scheduler/cfs"
+	_ "dat320/lab4/scheduler/fifo"
+	_ "dat320/lab4/scheduler/mlfq"
+	_ "dat320/lab4/scheduler/rr"
+	_ "dat320/lab4/scheduler/sjf"
+	_ "dat320/lab4/scheduler/steal"
+	_ "dat320/lab4/scheduler/stride"
+)
+
+func TestNames(t *testing.T) {
+	want := []string{"cfs", "fifo", "mlfq", "rr", "sjf", "stcf", "steal", "stride"}
+	if got := scheduler.Names(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Names() = %v, want %v", got, want)
+	}
+}
+
+var newTests = []struct {
+	name    string
+	opts    scheduler.Options
+	wantErr bool
+}{
+	{"fifo", scheduler.Options{}, false},
+	{"sjf", scheduler.Options{}, false},
+	{"stcf", scheduler.Options{}, false},
+	{"rr", scheduler.Options{Quantum: 2 * time.Millisecond}, false},
+	{"rr", scheduler.Options{}, true},
+	{"stride", scheduler.Options{Quantum: 2 * time.Millisecond}, false},
+	{"stride", scheduler.Options{}, true},
+	{"lottery-ish", scheduler.Options{}, true},
+}
+
+func TestNew(t *testing.T) {
+	for _, test := range newTests {
+		s, err := scheduler.New(test.name, cpu.NewCPUs(1), test.opts)
+		if (err != nil) != test.wantErr {
+			t.Errorf("New(%q, %+v) error = %v, wantErr %t", test.name, test.opts, err, test.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if got := s.Len(); got != 0 {
+			t.Errorf("New(%q).Len() = %d, want 0", test.name, got)
+		}
+		s.Add(job.New(0, 10*time.Millisecond))
+		if got := s.Len(); got != 1 {
+			t.Errorf("New(%q).Add(); Len() = %d, want 1", test.name, got)
+		}
+		if got := len(s.Running()); got != 0 {
+			t.Errorf("New(%q).Running() = %d jobs, want 0", test.name, got)
+		}
+	}
+}
+
+func TestNewNoCPUs(t *testing.T) {
+	if _, err := scheduler.New("fifo", nil, scheduler.Options{}); err == nil {
+		t.Error("New(\"fifo\", nil) = nil error, want error")
+	}
+}
+
+func TestNewSwitchCost(t *testing.T) {
+	opts := scheduler.Options{Quantum: 2 * time.Millisecond}
+	for _, name := range []string{"rr", "stride", "steal", "mlfq", "cfs"} {
+		cpus := cpu.NewCPUs(2)
+		cpus[1].SetContextSwitchCost(opts.Quantum)
+		if _, err := scheduler.New(name, cpus, opts); err == nil {
+			t.Errorf("New(%q) with switch cost %v = nil error, want error", name, opts.Quantum)
+		}
+	}
+	opts.SwitchCost = opts.Quantum
+	if _, err := scheduler.New("rr", cpu.NewCPUs(1), opts); err == nil {
+		t.Errorf("New(\"rr\", %+v) = nil error, want error", opts)
+	}
+	// steal without a quantum runs jobs to completion, so any cost is allowed
+	if _, err := scheduler.New("steal", cpu.NewCPUs(1), scheduler.Options{SwitchCost: opts.SwitchCost}); err != nil {
+		t.Errorf("New(\"steal\") without a quantum = %v, want nil error", err)
+	}
+	opts.SwitchCost = -time.Millisecond
+	if _, err := scheduler.New("fifo", cpu.NewCPUs(1), opts); err == nil {
+		t.Errorf("New(\"fifo\", %+v) = nil error, want error", opts)
+	}
+	opts.SwitchCost = time.Millisecond
+	cpus := cpu.NewCPUs(2)
+	if _, err := scheduler.New("rr", cpus, opts); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range cpus {
+		if got := c.ContextSwitchCost(); got != opts.SwitchCost {
+			t.Errorf("%s: ContextSwitchCost() = %v, want %v", c.Header(), got, opts.SwitchCost)
+		}
+	}
+}
+
+func TestNewCaches(t *testing.T) {
+	opts := scheduler.Options{CacheSize: 4, CacheWarmup: time.Millisecond, WarmSpeed: 2}
+	cpus := cpu.NewCPUs(2)
+	if _, err := scheduler.New("fifo", cpus, opts); err != nil {
+		t.Fatal(err)
+	}
+	if cpus[0].Cache() == nil || cpus[1].Cache() == nil || cpus[0].Cache() == cpus[1].Cache() {
+		t.Errorf("New(%+v) did not attach a cache to each CPU", opts)
+	}
+	for _, opts := range []scheduler.Options{{CacheSize: -1}, {CacheSize: 4, WarmSpeed: 0}} {
+		if _, err := scheduler.New("fifo", cpu.NewCPUs(1), opts); err == nil {
+			t.Errorf("New(\"fifo\", %+v) = nil error, want error", opts)
+		}
+	}
+}
+
+func TestSynchronized(t *testing.T) {
+	cpus := cpu.NewCPUs(2)
+	inner, err := scheduler.New("rr", cpus, scheduler.Options{Quantum: 2 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := scheduler.Synchronized(inner)
+	var schedule system.Schedule
+	for i := 0; i < 10; i++ {
+		schedule = append(schedule, &system.Entry{Job: job.NewIO(0, time.Millisecond, time.Millisecond, time.Millisecond)})
+	}
+	// monitor the scheduler while the simulation runs
+	stop, polls := make(chan struct{}), make(chan int)
+	go func() {
+		n := 0
+		for {
+			select {
+			case <-stop:
+				polls <- n
+				return
+			default:
+				s.Len()
+				s.Running()
+				n++
+			}
+		}
+	}()
+	jobs, err := system.New(s, schedule).RunParallel(cpus)
+	close(stop)
+	<-polls
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, j := range jobs {
+		if j.Remaining() > 0 || j.Blockings() != 1 {
+			t.Errorf("job %s: Remaining() = %v, Blockings() = %d; want 0, 1", j.Name(), j.Remaining(), j.Blockings())
+		}
+	}
+}
@@ -1,32 +1,52 @@
 package rr
 
 import (
+	"dat320/lab4/scheduler"
 	"dat320/lab4/scheduler/cpu"
 	"dat320/lab4/scheduler/job"
 	"time"
 )
 
+// roundRobin dispatches jobs from a single queue shared by all CPUs,
+// preempting the running jobs at every quantum boundary.
 type roundRobin struct {
 	queue   job.Jobs
-	cpu     *cpu.CPU
+	cpus    []*cpu.CPU
 	quantum time.Duration
-	//job     *job.Job
 }
 
+func init() {
+	scheduler.Register("rr", func(cpus []*cpu.CPU, opts scheduler.Options) (scheduler.Scheduler, error) {
+		if err := scheduler.RequireQuantum(opts); err != nil {
+			return nil, err
+		}
+		if err := scheduler.CheckSwitchCost(cpus, opts.Quantum); err != nil {
+			return nil, err
+		}
+		return New(cpus, opts.Quantum), nil
+	})
+}
+
+var (
+	_ scheduler.Scheduler = (*roundRobin)(nil)
+	_ scheduler.Tickless  = (*roundRobin)(nil)
+)
+
 func New(cpus []*cpu.CPU, quantum time.Duration) *roundRobin {
-	// TODO(student) construct new RR scheduler
-	if len(cpus) != 1 {
-		panic("fifo scheduler supports only a single CPU")
+	if len(cpus) == 0 {
+		panic("rr scheduler requires at least one CPU")
+	}
+	if err := scheduler.CheckSwitchCost(cpus, quantum); err != nil {
+		panic("rr scheduler: " + err.Error())
 	}
 	return &roundRobin{
-		cpu:     cpus[0],
+		cpus:    cpus,
 		queue:   make(job.Jobs, 0),
 		quantum: quantum,
-		//job:     cpus[0].CurrentJob(),
 	}
 }
+
 func (rr *roundRobin) Add(job *job.Job) {
-	// TODO(student) Add job to queue
 	rr.queue = append(rr.queue, job)
 }
 
@@ -35,28 +55,30 @@ func (rr *roundRobin) Add(job *job.Job) {
 // the Tick method may assign new jobs to the CPU before returning.
 func (rr *roundRobin) Tick(systemTime time.Duration) int {
 	jobsFinished := 0
-	if rr.cpu.IsRunning() { //cpu is running evaluates to true
-		if rr.cpu.Tick() { //cpu.Tick() //increase jobsfinished
-			jobsFinished++ //increment the job finished
+	sliceExhausted := systemTime%rr.quantum == 0
+	preempted := make([]bool, len(rr.cpus))
+	for i, c := range rr.cpus {
+		if c.IsRunning() && c.Tick() {
+			jobsFinished++
+		}
+		if sliceExhausted && c.IsRunning() {
+			// put the preempted job at the back of the queue
+			rr.Add(c.CurrentJob())
+			preempted[i] = true
 		}
 	}
-	if systemTime%rr.quantum == 0 { //time slice exhausted?
-		//check to see if current job is finished
-		if rr.cpu.CurrentJob() == nil { //check if current job finished
-			//jobsFinished++
-		} else { //no
-			rr.Add(rr.cpu.CurrentJob()) //add to the list of jobs to be run
+	for i, c := range rr.cpus {
+		if preempted[i] || !c.IsRunning() {
+			rr.reassign(c)
 		}
-		rr.reassign() //then reassign
 	}
 	return jobsFinished
 }
 
-// reassign assigns a job to the cpu
-func (rr *roundRobin) reassign() {
+// reassign assigns a job to the given CPU
+func (rr *roundRobin) reassign(c *cpu.CPU) {
 	nxtJob := rr.getNewJob()
-	rr.cpu.Assign(nxtJob)
-	// TODO(student) Implement reassign and use it from Tick
+	c.Assign(nxtJob)
 }
 
 // getNewJob finds a new job to run on the CPU, removes the job from the queue and returns the job
@@ -68,3 +90,18 @@ func (rr *roundRobin) getNewJob() *job.Job {
 	rr.queue = rr.queue[1:]
 	return removedJob
 }
+
+// NextDecision returns the next quantum boundary.
+func (rr *roundRobin) NextDecision(now time.Duration) time.Duration {
+	return (now/rr.quantum + 1) * rr.quantum
+}
+
+// Len returns the number of jobs waiting in the queue.
+func (rr *roundRobin) Len() int {
+	return len(rr.queue)
+}
+
+// Running returns the jobs currently running on the CPUs.
+func (rr *roundRobin) Running() job.Jobs {
+	return cpu.Running(rr.cpus)
+}
@@ -0,0 +1,77 @@
+package rr
+
+import (
+	"dat320/lab4/scheduler/cpu"
+	"dat320/lab4/scheduler/job"
+	"dat320/lab4/scheduler/system"
+	"testing"
+	"time"
+)
+
+func TestRoundRobinMultiCPU(t *testing.T) {
+	const ms = time.Millisecond
+	schedule := system.Schedule{
+		{Job: job.New(0, 3*ms), Arrival: 0},
+		{Job: job.New(0, 3*ms), Arrival: 0},
+		{Job: job.New(0, 2*ms), Arrival: 0},
+	}
+	rr := New(cpu.NewCPUs(2), 2*ms)
+	sys := system.New(rr, schedule)
+	sys.OnTick(func(now time.Duration) {
+		// all jobs have arrived; no job is lost or run twice
+		unfinished := 0
+		for _, entry := range schedule {
+			if entry.Job.Remaining() > 0 {
+				unfinished++
+			}
+		}
+		if got := len(rr.Running()) + rr.Len(); got != unfinished {
+			t.Fatalf("%v: Running()+Len() = %d, want %d", now, got, unfinished)
+		}
+	})
+	jobs, err := sys.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantTurnaround := []time.Duration{3 * ms, 4 * ms, 4 * ms}
+	wantResponse := []time.Duration{0, 0, 2 * ms}
+	wantSwitches := []int{1, 1, 0}
+	for i, j := range jobs {
+		if got := j.TurnaroundTime(); got != wantTurnaround[i] {
+			t.Errorf("job %s: TurnaroundTime() = %v, want %v", j.Name(), got, wantTurnaround[i])
+		}
+		if got := j.ResponseTime(); got != wantResponse[i] {
+			t.Errorf("job %s: ResponseTime() = %v, want %v", j.Name(), got, wantResponse[i])
+		}
+		if got := j.ContextSwitches(); got != wantSwitches[i] {
+			t.Errorf("job %s: ContextSwitches() = %d, want %d", j.Name(), got, wantSwitches[i])
+		}
+	}
+}
+
+func TestRoundRobinSwitchCost(t *testing.T) {
+	const ms = time.Millisecond
+	cpus := cpu.NewCPUs(1)
+	cpus[0].SetContextSwitchCost(1 * ms)
+	schedule := system.Schedule{
+		{Job: job.New(0, 3*ms), Arrival: 0},
+		{Job: job.New(0, 3*ms), Arrival: 0},
+	}
+	jobs, err := system.Run(New(cpus, 2*ms), schedule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, j := range jobs {
+		if got := j.RunTime(); got != 3*ms {
+			t.Errorf("job %s: RunTime() = %v, want 3ms", j.Name(), got)
+		}
+	}
+	// a switch cost of a whole quantum would leave no time to run the jobs
+	cpus[0].SetContextSwitchCost(2 * ms)
+	defer func() {
+		if recover() == nil {
+			t.Error("New() with a switch cost equal to the quantum did not panic")
+		}
+	}()
+	New(cpus, 2*ms)
+}
@@ -0,0 +1,195 @@
+// Package rt implements real-time scheduling of periodic and sporadic tasks.
+// It provides earliest deadline first (EDF) and rate-monotonic (RM)
+// schedulers, schedulability tests for task sets, and reporting of
+// deadline misses during simulation.
+//
+// Both schedulers are preemptive and global: with several CPUs, the jobs
+// with the highest priority run on any CPU. EDF prioritizes the job with
+// the earliest absolute deadline, RM the job with the shortest period.
+// Jobs without a deadline or period have the lowest priority. A job that
+// misses its deadline keeps running until it finishes.
+package rt
+
+import (
+	"dat320/lab4/scheduler"
+	"dat320/lab4/scheduler/cpu"
+	"dat320/lab4/scheduler/job"
+	"math"
+	"time"
+)
+
+func init() {
+	scheduler.Register("edf", func(cpus []*cpu.CPU, _ scheduler.Options) (scheduler.Scheduler, error) {
+		return NewEDF(cpus), nil
+	})
+	scheduler.Register("rm", func(cpus []*cpu.CPU, _ scheduler.Options) (scheduler.Scheduler, error) {
+		return NewRM(cpus), nil
+	})
+}
+
+var _ scheduler.Scheduler = (*rt)(nil)
+
+// Miss records a job that missed its deadline.
+type Miss struct {
+	JobID    int
+	Job      string        // name of the job
+	Deadline time.Duration // absolute deadline of the job
+	Detected time.Duration // system time when the miss was detected
+}
+
+type rt struct {
+	queue  job.Jobs
+	cpus   []*cpu.CPU
+	key    func(*job.Job) time.Duration // priority of a job; lower is higher priority
+	missed map[*job.Job]bool
+	misses []Miss
+}
+
+// NewEDF returns an earliest deadline first scheduler.
+func NewEDF(cpus []*cpu.CPU) *rt {
+	return newRT(cpus, func(j *job.Job) time.Duration { return orLowest(j.Deadline) })
+}
+
+// NewRM returns a rate-monotonic scheduler.
+func NewRM(cpus []*cpu.CPU) *rt {
+	return newRT(cpus, func(j *job.Job) time.Duration { return orLowest(j.Period) })
+}
+
+func newRT(cpus []*cpu.CPU, key func(*job.Job) time.Duration) *rt {
+	if len(cpus) == 0 {
+		panic("rt scheduler requires at least one CPU")
+	}
+	return &rt{
+		cpus:   cpus,
+		queue:  make(job.Jobs, 0),
+		key:    key,
+		missed: make(map[*job.Job]bool),
+	}
+}
+
+// orLowest returns d, or the lowest priority if d is zero.
+func orLowest(d time.Duration) time.Duration {
+	if d == 0 {
+		return math.MaxInt64
+	}
+	return d
+}
+
+// Add adds the job to the queue.
+func (r *rt) Add(job *job.Job) {
+	r.queue = append(r.queue, job)
+}
+
+// Tick runs the scheduled jobs for the system time, and returns
+// the number of jobs finished in this tick. Depending on scheduler requirements,
+// the Tick method may assign new jobs to the CPU before returning.
+func (r *rt) Tick(systemTime time.Duration) int {
+	jobsFinished := 0
+	for _, c := range r.cpus {
+		if !c.IsRunning() {
+			continue
+		}
+		current := c.CurrentJob()
+		if c.Tick() {
+			jobsFinished++
+			// a job that blocked past its deadline is only caught when it finishes
+			if current.Deadline > 0 && current.Finished() > current.Deadline {
+				r.miss(current, systemTime)
+			}
+		}
+	}
+	r.checkDeadlines(systemTime)
+	for _, c := range r.cpus {
+		if !c.IsRunning() {
+			r.reassign(c)
+		}
+	}
+	r.preempt()
+	return jobsFinished
+}
+
+// checkDeadlines records a miss for every unfinished job whose deadline has passed.
+func (r *rt) checkDeadlines(systemTime time.Duration) {
+	for _, j := range append(r.Running(), r.queue...) {
+		if j.Deadline > 0 && systemTime >= j.Deadline {
+			r.miss(j, systemTime)
+		}
+	}
+}
+
+// miss records that the job missed its deadline, unless already recorded.
+func (r *rt) miss(j *job.Job, systemTime time.Duration) {
+	if r.missed[j] {
+		return
+	}
+	r.missed[j] = true
+	r.misses = append(r.misses, Miss{JobID: j.ID(), Job: j.Name(), Deadline: j.Deadline, Detected: systemTime})
+}
+
+// Misses returns the deadline misses in the order they were detected.
+func (r *rt) Misses() []Miss {
+	return r.misses
+}
+
+// preempt replaces the running job with the lowest priority by the queued
+// job with the highest priority, for as long as the queued job has higher priority.
+func (r *rt) preempt() {
+	for len(r.queue) > 0 {
+		highest := r.queue[r.highest()]
+		lowest := r.lowestRunning()
+		if lowest == nil || r.key(highest) >= r.key(lowest.CurrentJob()) {
+			return
+		}
+		r.queue = append(r.queue, lowest.CurrentJob())
+		r.reassign(lowest)
+	}
+}
+
+// lowestRunning returns the CPU running the job with the lowest priority,
+// or nil if all CPUs are idle.
+func (r *rt) lowestRunning() *cpu.CPU {
+	var lowest *cpu.CPU
+	for _, c := range r.cpus {
+		if !c.IsRunning() {
+			continue
+		}
+		if lowest == nil || r.key(c.CurrentJob()) > r.key(lowest.CurrentJob()) {
+			lowest = c
+		}
+	}
+	return lowest
+}
+
+// reassign assigns the queued job with the highest priority to the given CPU.
+func (r *rt) reassign(c *cpu.CPU) {
+	if len(r.queue) == 0 {
+		c.Assign(nil)
+		return
+	}
+	i := r.highest()
+	nxtJob := r.queue[i]
+	r.queue = append(r.queue[:i], r.queue[i+1:]...)
+	c.Assign(nxtJob)
+}
+
+// highest returns the index of the queued job with the highest priority;
+// ties are broken in queue order.
+func (r *rt) highest() int {
+	highest := 0
+	for i := 1; i < len(r.queue); i++ {
+		if r.key(r.queue[i]) < r.key(r.queue[highest]) {
+			highest = i
+		}
+	}
+	return highest
+}
+
+// Len returns the number of jobs waiting in the queue.
+func (r *rt) Len() int {
+	return len(r.queue)
+}
+
+// Running returns the jobs currently running on the CPUs.
+func (r *rt) Running() job.Jobs {
+	return cpu.Running(r.cpus)
+}
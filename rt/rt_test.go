@@ -0,0 +1,121 @@
+package rt
+
+import (
+	"dat320/lab4/scheduler/cpu"
+	"dat320/lab4/scheduler/system"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+const ms = time.Millisecond
+
+func TestUtilizationBound(t *testing.T) {
+	tests := []struct {
+		n    int
+		want float64
+	}{
+		{1, 1},
+		{2, 0.8284},
+		{3, 0.7798},
+	}
+	for _, test := range tests {
+		if got := UtilizationBound(test.n); math.Abs(got-test.want) > 1e-4 {
+			t.Errorf("UtilizationBound(%d) = %.4f, want %.4f", test.n, got, test.want)
+		}
+	}
+}
+
+func TestResponseTimes(t *testing.T) {
+	// U = 0.814 exceeds the bound for three tasks, but the set is schedulable.
+	ts := TaskSet{
+		{Name: "T3", Period: 13 * ms, WCET: 3 * ms},
+		{Name: "T1", Period: 4 * ms, WCET: 1 * ms},
+		{Name: "T2", Period: 6 * ms, WCET: 2 * ms},
+	}
+	if ts.RMBoundTest() {
+		t.Errorf("RMBoundTest() = true, want false for utilization %.3f", ts.Utilization())
+	}
+	got, ok, err := ts.ResponseTimes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Errorf("ResponseTimes() schedulable = false, want true")
+	}
+	want := []time.Duration{10 * ms, 1 * ms, 3 * ms}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("ResponseTimes()[%s] = %v, want %v", ts[i].Name, got[i], want[i])
+		}
+	}
+}
+
+// rmMisses is schedulable by EDF (U = 0.97), but not by RM.
+var rmMisses = TaskSet{
+	{Name: "T1", Period: 5 * ms, WCET: 2 * ms},
+	{Name: "T2", Period: 7 * ms, WCET: 4 * ms},
+}
+
+func TestSchedulabilityTests(t *testing.T) {
+	if !rmMisses.EDFTest() {
+		t.Errorf("EDFTest() = false, want true")
+	}
+	if _, ok, err := rmMisses.ResponseTimes(); err != nil || ok {
+		t.Errorf("ResponseTimes() schedulable = %t, %v; want false, nil", ok, err)
+	}
+	if _, _, err := (TaskSet{{Name: "T", WCET: 2 * ms}}).ResponseTimes(); !errors.Is(err, errInvalidTask) {
+		t.Errorf("ResponseTimes() with zero period: got %v, want %v", err, errInvalidTask)
+	}
+	if _, err := (TaskSet{{Name: "T", Period: 5 * ms, WCET: 2 * ms, Deadline: 1 * ms}}).Schedule(10*ms, 0); err == nil {
+		t.Errorf("Schedule() with deadline below WCET: got nil error")
+	}
+}
+
+func TestDeadlineMisses(t *testing.T) {
+	tests := []struct {
+		name     string
+		new      func([]*cpu.CPU) *rt
+		wantMiss bool
+	}{
+		{"EDF", NewEDF, false},
+		{"RM", NewRM, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			schedule, err := rmMisses.Schedule(35*ms, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s := test.new(cpu.NewCPUs(1))
+			if _, err := system.Run(s, schedule); err != nil {
+				t.Fatal(err)
+			}
+			misses := s.Misses()
+			if got := len(misses) > 0; got != test.wantMiss {
+				t.Fatalf("Misses() = %v, want misses: %t", misses, test.wantMiss)
+			}
+			// the first job of T2 is preempted by T1 at 5ms and finishes at 8ms
+			if test.wantMiss && (misses[0].Deadline != 7*ms || misses[0].Detected != 7*ms) {
+				t.Errorf("Misses()[0] = %+v, want deadline and detection at 7ms", misses[0])
+			}
+		})
+	}
+}
+
+func TestSporadicReleases(t *testing.T) {
+	ts := TaskSet{{Name: "S", Period: 5 * ms, WCET: 1 * ms, Sporadic: true}}
+	schedule, err := ts.Schedule(100*ms, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i < len(schedule); i++ {
+		if gap := schedule[i].Arrival - schedule[i-1].Arrival; gap < 5*ms {
+			t.Errorf("release %d: %v after previous release, want at least 5ms", i, gap)
+		}
+		if got, want := schedule[i].Job.Deadline, schedule[i].Arrival+5*ms; got != want {
+			t.Errorf("release %d: Deadline = %v, want %v", i, got, want)
+		}
+	}
+}
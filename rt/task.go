@@ -0,0 +1,199 @@
+package rt
+
+import (
+	"dat320/lab4/scheduler/job"
+	"dat320/lab4/scheduler/system"
+	"dat320/lab4/scheduler/system/systime"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+var (
+	errInvalidTask     = errors.New("invalid argument: period and WCET must be greater than 0")
+	errInvalidDeadline = errors.New("invalid argument: deadline must be between WCET and period")
+)
+
+// Task is a recurring real-time task. A periodic task releases a job every
+// period, starting at its offset; a sporadic task releases jobs at least one
+// period apart. Each job runs for the task's worst-case execution time (WCET)
+// and must finish within the task's relative deadline.
+type Task struct {
+	Name     string
+	Period   time.Duration // period, or minimum inter-arrival time of a sporadic task
+	Deadline time.Duration // relative deadline; zero means equal to the period
+	WCET     time.Duration // worst-case execution time
+	Offset   time.Duration // release time of the first job
+	Sporadic bool
+}
+
+// RelativeDeadline returns the task's relative deadline.
+func (t Task) RelativeDeadline() time.Duration {
+	if t.Deadline == 0 {
+		return t.Period
+	}
+	return t.Deadline
+}
+
+// Utilization returns the fraction of a CPU used by the task.
+func (t Task) Utilization() float64 {
+	return float64(t.WCET) / float64(t.Period)
+}
+
+func (t Task) validate() error {
+	switch {
+	case t.Period <= 0 || t.WCET <= 0:
+		return fmt.Errorf("task %s: %w", t.Name, errInvalidTask)
+	case t.RelativeDeadline() < t.WCET || t.RelativeDeadline() > t.Period:
+		return fmt.Errorf("task %s: %w", t.Name, errInvalidDeadline)
+	}
+	return nil
+}
+
+// TaskSet is a set of real-time tasks.
+type TaskSet []Task
+
+func (ts TaskSet) validate() error {
+	for _, t := range ts {
+		if err := t.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Utilization returns the total utilization of the task set.
+func (ts TaskSet) Utilization() float64 {
+	u := 0.0
+	for _, t := range ts {
+		u += t.Utilization()
+	}
+	return u
+}
+
+// UtilizationBound returns the Liu and Layland utilization bound n(2^(1/n) - 1)
+// for n tasks under rate-monotonic scheduling.
+func UtilizationBound(n int) float64 {
+	if n <= 0 {
+		return 1
+	}
+	return float64(n) * (math.Pow(2, 1/float64(n)) - 1)
+}
+
+// RMBoundTest reports whether the task set passes the Liu and Layland
+// utilization bound. The test is sufficient but not necessary: a task set
+// failing it may still be schedulable; see ResponseTimes.
+func (ts TaskSet) RMBoundTest() bool {
+	return ts.Utilization() <= UtilizationBound(len(ts))
+}
+
+// EDFTest reports whether the task set is schedulable by EDF on one CPU,
+// that is, whether its density, the sum of WCET/min(deadline, period),
+// is at most 1. The test is exact when deadlines equal periods.
+func (ts TaskSet) EDFTest() bool {
+	density := 0.0
+	for _, t := range ts {
+		density += float64(t.WCET) / float64(t.RelativeDeadline())
+	}
+	return density <= 1
+}
+
+// ResponseTimes returns the worst-case response time of each task on one CPU
+// under deadline-monotonic priorities, which are rate-monotonic priorities when
+// deadlines equal periods, and reports whether every task meets its deadline.
+// The response time R of a task is the smallest solution of
+//
+//	R = WCET + sum over higher priority tasks j of ceil(R/Period_j) * WCET_j
+//
+// The iteration stops as soon as R exceeds the task's deadline, in which
+// case the returned response time is the first value past the deadline.
+// ResponseTimes returns an error if a task has an invalid period, WCET or deadline.
+func (ts TaskSet) ResponseTimes() ([]time.Duration, bool, error) {
+	if err := ts.validate(); err != nil {
+		return nil, false, err
+	}
+	order := ts.priorityOrder()
+	response := make([]time.Duration, len(ts))
+	schedulable := true
+	for rank, i := range order {
+		t := ts[i]
+		r := t.WCET
+		for {
+			next := demand(t, ts, order[:rank], r)
+			if next > t.RelativeDeadline() {
+				r, schedulable = next, false
+				break
+			}
+			if next == r {
+				break
+			}
+			r = next
+		}
+		response[i] = r
+	}
+	return response, schedulable, nil
+}
+
+// demand returns the right-hand side of the response time equation for
+// task t at response time r, given the tasks with higher priority.
+func demand(t Task, ts TaskSet, higher []int, r time.Duration) time.Duration {
+	d := t.WCET
+	for _, j := range higher {
+		hp := ts[j]
+		d += (r + hp.Period - 1) / hp.Period * hp.WCET
+	}
+	return d
+}
+
+// priorityOrder returns the indices of the tasks from highest to lowest
+// deadline-monotonic priority; ties are broken in task set order.
+func (ts TaskSet) priorityOrder() []int {
+	order := make([]int, len(ts))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return ts[order[a]].RelativeDeadline() < ts[order[b]].RelativeDeadline()
+	})
+	return order
+}
+
+// Schedule releases the jobs of the task set up to, but not including,
+// the horizon, and returns them ordered by release time. Each job's
+// Deadline is set to its absolute deadline and its Period to the task's
+// period. Sporadic tasks are delayed by an extra random number of ticks
+// below their period between releases, drawn from the given seed.
+// Each call creates new jobs with their own job.Factory.
+func (ts TaskSet) Schedule(horizon time.Duration, seed int64) (system.Schedule, error) {
+	type release struct {
+		task int
+		at   time.Duration
+	}
+	if err := ts.validate(); err != nil {
+		return nil, err
+	}
+	rng := rand.New(rand.NewSource(seed))
+	var releases []release
+	for i, t := range ts {
+		for at := t.Offset; at < horizon; at += t.Period {
+			releases = append(releases, release{task: i, at: at})
+			if ticks := int64(t.Period / systime.TickDuration); t.Sporadic && ticks > 0 {
+				at += time.Duration(rng.Int63n(ticks)) * systime.TickDuration
+			}
+		}
+	}
+	sort.SliceStable(releases, func(i, j int) bool { return releases[i].at < releases[j].at })
+	schedule := make(system.Schedule, len(releases))
+	factory := job.NewFactory()
+	for i, r := range releases {
+		t := ts[r.task]
+		j := factory.New(0, t.WCET)
+		j.Deadline = r.at + t.RelativeDeadline()
+		j.Period = t.Period
+		schedule[i] = &system.Entry{Job: j, Arrival: r.at}
+	}
+	return schedule, nil
+}
@@ -0,0 +1,73 @@
+// Package scheduler defines the interface shared by all scheduling policies
+// and a registry for constructing a policy by name.
+//
+// Policies register themselves when their package is imported, e.g.
+//
+//	import _ "dat320/lab4/scheduler/rr"
+//
+//	s, err := scheduler.New("rr", cpu.NewCPUs(1), scheduler.Options{Quantum: 2 * time.Millisecond})
+package scheduler
+
+import (
+	"dat320/lab4/scheduler/cpu"
+	"dat320/lab4/scheduler/job"
+	"math"
+	"time"
+)
+
+// Scheduler is implemented by all scheduling policies.
+type Scheduler interface {
+	// Add adds a job to the scheduler's run queue.
+	Add(job *job.Job)
+	// Tick runs the scheduled jobs for the system time, and returns
+	// the number of jobs finished in this tick.
+	Tick(systemTime time.Duration) int
+	// Len returns the number of jobs waiting in the run queue,
+	// not counting the jobs currently running on a CPU.
+	Len() int
+	// Running returns the jobs currently running on the scheduler's CPUs.
+	Running() job.Jobs
+}
+
+// Blocker is implemented by schedulers that need to be notified when a job
+// blocks for I/O, and when its I/O completes. When the I/O of a job completes,
+// the job is passed to Unblock if the scheduler implements Blocker, and to Add otherwise.
+type Blocker interface {
+	// Block is called after a running job has blocked for I/O,
+	// and has been taken off its CPU.
+	Block(job *job.Job)
+	// Unblock makes a job runnable again after its I/O completed.
+	Unblock(job *job.Job)
+}
+
+// Never is returned by Tickless.NextDecision for schedulers that only
+// make decisions when jobs arrive, finish, block or unblock.
+const Never = time.Duration(math.MaxInt64)
+
+// Tickless is implemented by schedulers that can be simulated without
+// calling Tick at every tick; see system.System.RunTickless. Until the
+// time returned by NextDecision, Tick must do nothing but run the jobs on
+// the CPUs, unless a job arrives, finishes, blocks or unblocks.
+type Tickless interface {
+	// NextDecision returns the earliest system time after now at which the
+	// scheduler may decide to run another job on a CPU, such as the end of
+	// the current quantum, or Never.
+	NextDecision(now time.Duration) time.Duration
+}
+
+// Options holds the policy parameters passed to a Factory.
+// Policies ignore the options they do not use.
+type Options struct {
+	Quantum     time.Duration // time slice for preemptive policies
+	Levels      int           // number of priority levels (mlfq)
+	BoostPeriod time.Duration // interval between priority boosts (mlfq)
+	Seed        int64         // random number generator seed (lottery)
+	Aging       time.Duration // waiting time per priority raise; zero disables aging (priority)
+	SwitchCost  time.Duration // context switch cost set on every CPU by New; zero keeps the CPUs' cost
+	CacheSize   int           // capacity of the caches attached to the CPUs by New; zero attaches none
+	CacheWarmup time.Duration // time for a job's working set to become warm in a cache
+	WarmSpeed   int           // speed of jobs whose working set is warm
+}
+
+// Factory constructs a scheduler for the given CPUs.
+type Factory func(cpus []*cpu.CPU, opts Options) (Scheduler, error)
@@ -1,32 +1,64 @@
 package sjf
 
 import (
+	"dat320/lab4/scheduler"
 	"dat320/lab4/scheduler/cpu"
 	"dat320/lab4/scheduler/job"
-	"dat320/lab4/scheduler/system/systime"
 	"time"
 )
 
 type sjf struct {
 	queue job.Jobs
-	cpu   *cpu.CPU
-	//ODO(student) add missing fields, if necessary
-	job       *job.Job
-	remaining time.Duration
+	cpus  []*cpu.CPU
+	// preemptive selects shortest time-to-completion first (STCF);
+	// otherwise jobs run to completion once started.
+	preemptive bool
 }
 
+func init() {
+	scheduler.Register("sjf", func(cpus []*cpu.CPU, _ scheduler.Options) (scheduler.Scheduler, error) {
+		return New(cpus), nil
+	})
+	scheduler.Register("stcf", func(cpus []*cpu.CPU, _ scheduler.Options) (scheduler.Scheduler, error) {
+		return NewSTCF(cpus), nil
+	})
+}
+
+var (
+	_ scheduler.Scheduler = (*sjf)(nil)
+	_ scheduler.Tickless  = (*sjf)(nil)
+)
+
+// New returns a non-preemptive shortest job first scheduler.
+// An idle CPU is assigned the queued job with the shortest estimated duration.
 func New(cpus []*cpu.CPU) *sjf {
-	// ODO(student) construct new RR scheduler
+	return newSJF(cpus, false)
+}
+
+// NewSTCF returns a preemptive shortest time-to-completion first scheduler.
+// A running job is preempted whenever a queued job has less remaining time.
+func NewSTCF(cpus []*cpu.CPU) *sjf {
+	return newSJF(cpus, true)
+}
 
+func newSJF(cpus []*cpu.CPU, preemptive bool) *sjf {
+	if len(cpus) == 0 {
+		panic("sjf scheduler requires at least one CPU")
+	}
 	return &sjf{
-		cpu:   cpus[0],
-		queue: make(job.Jobs, 0),
-		job:   cpus[0].CurrentJob(),
+		cpus:       cpus,
+		queue:      make(job.Jobs, 0),
+		preemptive: preemptive,
 	}
 }
 
+// Add adds the job to the queue. STCF does not preempt in Add: a job added
+// at time t arrives while the tick ending at t is still in progress, and
+// preempting then would run it before its arrival. Instead, the new job is
+// compared against the running jobs at the end of Tick(t), once they have
+// run up to t; the longest running job is preempted if the new job has
+// less remaining time, so the new job starts running at t.
 func (s *sjf) Add(job *job.Job) {
-	// ODO(student) Add job to queue
 	s.queue = append(s.queue, job)
 }
 
@@ -35,34 +67,97 @@ func (s *sjf) Add(job *job.Job) {
 // the Tick method may assign new jobs to the CPU before returning.
 func (s *sjf) Tick(systemTime time.Duration) int {
 	jobsFinished := 0
-	s.remaining -= systemTime * systime.TickDuration
-	done := false
-	if s.remaining <= 0 {
-		done = true
-		if done {
+	for _, c := range s.cpus {
+		if c.IsRunning() && c.Tick() {
 			jobsFinished++
-			s.reassign()
-			return jobsFinished
 		}
-	} else {
-		s.reassign()
+	}
+	for _, c := range s.cpus {
+		if !c.IsRunning() {
+			s.reassign(c)
+		}
+	}
+	if s.preemptive {
+		s.preempt()
 	}
 	return jobsFinished
 }
 
-// reassign assigns a job to the cpu
-func (s *sjf) reassign() {
-	s.cpu.Assign(s.getNewJob())
-	// ODO(student) Implement reassign and use it from Tick
+// preempt replaces the running job with the most remaining time by the
+// queued job with the least remaining time, for as long as the queued job is shorter.
+func (s *sjf) preempt() {
+	for len(s.queue) > 0 {
+		shortest := s.queue[s.shortest()]
+		longest := s.longestRunning()
+		if longest == nil || shortest.Remaining() >= longest.CurrentJob().Remaining() {
+			return
+		}
+		s.queue = append(s.queue, longest.CurrentJob())
+		s.reassign(longest)
+	}
+}
+
+// longestRunning returns the CPU running the job with the most remaining time,
+// or nil if all CPUs are idle.
+func (s *sjf) longestRunning() *cpu.CPU {
+	var longest *cpu.CPU
+	for _, c := range s.cpus {
+		if !c.IsRunning() {
+			continue
+		}
+		if longest == nil || c.CurrentJob().Remaining() > longest.CurrentJob().Remaining() {
+			longest = c
+		}
+	}
+	return longest
+}
+
+// reassign assigns a job to the given CPU
+func (s *sjf) reassign(c *cpu.CPU) {
+	c.Assign(s.getNewJob())
 }
 
 // getNewJob finds a new job to run on the CPU, removes the job from the queue and returns the job
 func (s *sjf) getNewJob() *job.Job {
-	//ODO(student) Implement getNewJob and use it from reassign
 	if len(s.queue) == 0 {
 		return nil
 	}
-	removedJob := s.queue[0]
-	s.queue = s.queue[1:]
+	i := s.shortest()
+	removedJob := s.queue[i]
+	s.queue = append(s.queue[:i], s.queue[i+1:]...)
 	return removedJob
 }
+
+// shortest returns the index of the shortest job in the queue; ties are
+// broken in queue order. SJF compares the estimated duration of the jobs,
+// while STCF compares their remaining time.
+func (s *sjf) shortest() int {
+	shortest := 0
+	for i := 1; i < len(s.queue); i++ {
+		if s.preemptive {
+			if s.queue[i].Remaining() < s.queue[shortest].Remaining() {
+				shortest = i
+			}
+		} else if s.queue.Less(i, shortest) {
+			shortest = i
+		}
+	}
+	return shortest
+}
+
+// NextDecision returns scheduler.Never: SJF runs jobs to completion, and
+// STCF only preempts a job when a shorter job arrives, since the remaining
+// time of the running jobs only decreases.
+func (s *sjf) NextDecision(now time.Duration) time.Duration {
+	return scheduler.Never
+}
+
+// Len returns the number of jobs waiting in the queue.
+func (s *sjf) Len() int {
+	return len(s.queue)
+}
+
+// Running returns the jobs currently running on the CPUs.
+func (s *sjf) Running() job.Jobs {
+	return cpu.Running(s.cpus)
+}
@@ -0,0 +1,80 @@
+package sjf
+
+import (
+	"dat320/lab4/scheduler/cpu"
+	"dat320/lab4/scheduler/job"
+	"dat320/lab4/scheduler/system"
+	"dat320/lab4/scheduler/system/systime"
+	"testing"
+	"time"
+)
+
+const ms = time.Millisecond
+
+var sjfTests = []struct {
+	name           string
+	new            func([]*cpu.CPU) *sjf
+	wantTurnaround []time.Duration
+	wantResponse   []time.Duration
+}{
+	{"SJF", New, []time.Duration{5 * ms, 7 * ms, 5 * ms}, []time.Duration{0, 5 * ms, 4 * ms}},
+	{"STCF", NewSTCF, []time.Duration{8 * ms, 3 * ms, 1 * ms}, []time.Duration{0, 1 * ms, 0}},
+}
+
+func TestShortestJobFirst(t *testing.T) {
+	for _, test := range sjfTests {
+		t.Run(test.name, func(t *testing.T) {
+			clk := &systime.ManualClock{}
+			jobs := job.Jobs{job.New(0, 5*ms), job.New(0, 2*ms), job.New(0, 1*ms)}
+			arrivals := []time.Duration{0, 1 * ms, 1 * ms}
+			s := test.new(cpu.NewCPUs(1))
+			for finished := 0; finished < len(jobs); clk.Time += ms {
+				for i, j := range jobs {
+					if arrivals[i] == clk.Time {
+						j.Scheduled(clk)
+						s.Add(j)
+					}
+				}
+				finished += s.Tick(clk.Time)
+			}
+			for i, j := range jobs {
+				if got := j.TurnaroundTime(); got != test.wantTurnaround[i] {
+					t.Errorf("job %s: TurnaroundTime() = %v, want %v", j.Name(), got, test.wantTurnaround[i])
+				}
+				if got := j.ResponseTime(); got != test.wantResponse[i] {
+					t.Errorf("job %s: ResponseTime() = %v, want %v", j.Name(), got, test.wantResponse[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSTCFPreemptsAtArrival(t *testing.T) {
+	a, b := job.New(0, 5*ms), job.New(0, 1*ms)
+	s := NewSTCF(cpu.NewCPUs(1))
+	sys := system.New(s, system.Schedule{{Job: a, Arrival: 0}, {Job: b, Arrival: 2 * ms}})
+	sys.OnTick(func(now time.Duration) {
+		running := s.Running()
+		switch now {
+		case 2 * ms:
+			// B has been added, but A keeps running until the end of Tick(2ms)
+			if len(running) != 1 || running[0] != a || s.Len() != 1 {
+				t.Errorf("%v: Running() = %v, Len() = %d, want [%s], 1", now, running, s.Len(), a.Name())
+			}
+		case 3 * ms:
+			// A was preempted after running up to 2ms, and B started at 2ms
+			if len(running) != 1 || running[0] != b || a.Remaining() != 3*ms {
+				t.Errorf("%v: Running() = %v, A.Remaining() = %v, want [%s], 3ms", now, running, a.Remaining(), b.Name())
+			}
+		}
+	})
+	if _, err := sys.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.ResponseTime(); got != 0 {
+		t.Errorf("B: ResponseTime() = %v, want 0s", got)
+	}
+	if got := a.TurnaroundTime(); got != 6*ms {
+		t.Errorf("A: TurnaroundTime() = %v, want 6ms", got)
+	}
+}
@@ -0,0 +1,141 @@
+// Package steal implements a scheduler where each CPU has its own run queue,
+// and idle CPUs steal jobs from the most loaded peer.
+package steal
+
+import (
+	"dat320/lab4/scheduler"
+	"dat320/lab4/scheduler/cpu"
+	"dat320/lab4/scheduler/job"
+	"time"
+)
+
+type workStealing struct {
+	cpus    []*cpu.CPU
+	quantum time.Duration // zero means jobs run to completion
+	steals  []int         // number of jobs stolen by each CPU
+}
+
+func init() {
+	scheduler.Register("steal", func(cpus []*cpu.CPU, opts scheduler.Options) (scheduler.Scheduler, error) {
+		if opts.Quantum > 0 {
+			if err := scheduler.CheckSwitchCost(cpus, opts.Quantum); err != nil {
+				return nil, err
+			}
+		}
+		return New(cpus, opts.Quantum), nil
+	})
+}
+
+var _ scheduler.Scheduler = (*workStealing)(nil)
+
+// New returns a work stealing scheduler for the given CPUs. If quantum is
+// positive, running jobs are preempted at every quantum boundary and put at
+// the back of their own CPU's queue; otherwise jobs run to completion.
+// New panics if quantum is positive and a CPU's context switch cost is
+// not less than it.
+func New(cpus []*cpu.CPU, quantum time.Duration) *workStealing {
+	if len(cpus) == 0 {
+		panic("steal scheduler requires at least one CPU")
+	}
+	if quantum > 0 {
+		if err := scheduler.CheckSwitchCost(cpus, quantum); err != nil {
+			panic("steal scheduler: " + err.Error())
+		}
+	}
+	return &workStealing{
+		cpus:    cpus,
+		quantum: quantum,
+		steals:  make([]int, len(cpus)),
+	}
+}
+
+// Add places the job in the run queue of the least loaded CPU.
+func (ws *workStealing) Add(job *job.Job) {
+	target := ws.cpus[0]
+	for _, c := range ws.cpus[1:] {
+		if c.Load() < target.Load() {
+			target = c
+		}
+	}
+	target.Enqueue(job)
+}
+
+// Tick runs the scheduled jobs for the system time, and returns
+// the number of jobs finished in this tick. Idle CPUs take the next job
+// from their own queue, or steal one from the busiest peer.
+func (ws *workStealing) Tick(systemTime time.Duration) int {
+	jobsFinished := 0
+	sliceExhausted := ws.quantum > 0 && systemTime%ws.quantum == 0
+	preempted := make([]bool, len(ws.cpus))
+	for i, c := range ws.cpus {
+		if c.IsRunning() && c.Tick() {
+			jobsFinished++
+		}
+		if sliceExhausted && c.IsRunning() {
+			// preempted job stays on this CPU to keep its cache affinity
+			c.Enqueue(c.CurrentJob())
+			preempted[i] = true
+		}
+	}
+	// preempted CPUs are reassigned first, so that an idle CPU does not
+	// steal the job just preempted on a peer with no other work
+	for i := range ws.cpus {
+		if preempted[i] {
+			ws.reassign(i)
+		}
+	}
+	for i, c := range ws.cpus {
+		if !c.IsRunning() {
+			ws.reassign(i)
+		}
+	}
+	return jobsFinished
+}
+
+// reassign assigns the next job from its own queue to CPU i;
+// if its queue is empty, a job is stolen from the busiest peer.
+func (ws *workStealing) reassign(i int) {
+	c := ws.cpus[i]
+	nxtJob := c.Dequeue()
+	if nxtJob == nil {
+		if victim := ws.busiest(); victim != nil {
+			nxtJob = victim.Steal()
+			ws.steals[i]++
+		}
+	}
+	c.Assign(nxtJob)
+}
+
+// busiest returns the CPU with the most jobs waiting in its queue,
+// or nil if all queues are empty.
+func (ws *workStealing) busiest() *cpu.CPU {
+	var victim *cpu.CPU
+	for _, c := range ws.cpus {
+		if c.QueueLen() > 0 && (victim == nil || c.QueueLen() > victim.QueueLen()) {
+			victim = c
+		}
+	}
+	return victim
+}
+
+// Steals returns the number of jobs stolen by each CPU, indexed by
+// the CPU's position in the slice passed to New.
+func (ws *workStealing) Steals() []int {
+	steals := make([]int, len(ws.steals))
+	copy(steals, ws.steals)
+	return steals
+}
+
+// Len returns the number of jobs waiting in the CPUs' queues.
+func (ws *workStealing) Len() int {
+	n := 0
+	for _, c := range ws.cpus {
+		n += c.QueueLen()
+	}
+	return n
+}
+
+// Running returns the jobs currently running on the CPUs.
+func (ws *workStealing) Running() job.Jobs {
+	return cpu.Running(ws.cpus)
+}
@@ -0,0 +1,63 @@
+package steal
+
+import (
+	"dat320/lab4/scheduler/cpu"
+	"dat320/lab4/scheduler/job"
+	"dat320/lab4/scheduler/system/systime"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWorkStealing(t *testing.T) {
+	const ms = time.Millisecond
+	clk := &systime.ManualClock{}
+	// placement: CPU0 gets A and C, CPU1 gets B and D
+	jobs := job.Jobs{job.New(0, 4*ms), job.New(0, 1*ms), job.New(0, 1*ms), job.New(0, 1*ms)}
+	ws := New(cpu.NewCPUs(2), 0)
+	for _, j := range jobs {
+		j.Scheduled(clk)
+		ws.Add(j)
+	}
+	for finished := 0; finished < len(jobs); clk.Time += ms {
+		finished += ws.Tick(clk.Time)
+	}
+	// CPU1 runs out of work after D and steals C from CPU0
+	wantTurnaround := []time.Duration{4 * ms, 1 * ms, 3 * ms, 2 * ms}
+	for i, j := range jobs {
+		if got := j.TurnaroundTime(); got != wantTurnaround[i] {
+			t.Errorf("job %s: TurnaroundTime() = %v, want %v", j.Name(), got, wantTurnaround[i])
+		}
+	}
+	if got, want := ws.Steals(), []int{0, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Steals() = %v, want %v", got, want)
+	}
+	if got := ws.Len(); got != 0 {
+		t.Errorf("Len() = %d, want 0", got)
+	}
+}
+
+func TestWorkStealingPreempted(t *testing.T) {
+	const ms = time.Millisecond
+	clk := &systime.ManualClock{}
+	// placement: CPU0 gets A, CPU1 gets B
+	a, b := job.New(0, 1*ms), job.New(0, 12*ms)
+	ws := New(cpu.NewCPUs(2), 2*ms)
+	for _, j := range []*job.Job{a, b} {
+		j.Scheduled(clk)
+		ws.Add(j)
+	}
+	for finished := 0; finished < 2; clk.Time += ms {
+		finished += ws.Tick(clk.Time)
+	}
+	// CPU0 is idle after A, but B resumes on CPU1 at every quantum boundary
+	if got, want := ws.Steals(), []int{0, 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Steals() = %v, want %v", got, want)
+	}
+	if got := b.ContextSwitches(); got != 0 {
+		t.Errorf("job %s: ContextSwitches() = %d, want 0", b.Name(), got)
+	}
+	if got, want := b.TurnaroundTime(), 12*ms; got != want {
+		t.Errorf("job %s: TurnaroundTime() = %v, want %v", b.Name(), got, want)
+	}
+}
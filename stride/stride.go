@@ -1,32 +1,62 @@
 package stride
 
 import (
+	"dat320/lab4/scheduler"
 	"dat320/lab4/scheduler/cpu"
 	"dat320/lab4/scheduler/job"
-	"dat320/lab4/scheduler/system/systime"
 	"time"
 )
 
+// stride dispatches jobs from a single queue shared by all CPUs; at every
+// quantum boundary the running jobs are preempted and each CPU is given the
+// job with the lowest pass. A job's pass is advanced by its stride every time
+// it is given a quantum, so that over time each job runs in proportion to its tickets.
 type stride struct {
-	queue job.Jobs
-	cpu   *cpu.CPU
-	// TODO(student) add missing fields, if necessary
-	job       *job.Job
-	remaining time.Duration
-	quantum   time.Duration
+	queue      job.Jobs
+	cpus       []*cpu.CPU
+	quantum    time.Duration
+	globalPass int // pass of the most recently dispatched job
 }
 
+func init() {
+	scheduler.Register("stride", func(cpus []*cpu.CPU, opts scheduler.Options) (scheduler.Scheduler, error) {
+		if err := scheduler.RequireQuantum(opts); err != nil {
+			return nil, err
+		}
+		if err := scheduler.CheckSwitchCost(cpus, opts.Quantum); err != nil {
+			return nil, err
+		}
+		return New(cpus, opts.Quantum), nil
+	})
+}
+
+var (
+	_ scheduler.Scheduler = (*stride)(nil)
+	_ scheduler.Tickless  = (*stride)(nil)
+)
+
 func New(cpus []*cpu.CPU, quantum time.Duration) *stride {
-	// TODO(student) construct new stride scheduler
+	if len(cpus) == 0 {
+		panic("stride scheduler requires at least one CPU")
+	}
+	if err := scheduler.CheckSwitchCost(cpus, quantum); err != nil {
+		panic("stride scheduler: " + err.Error())
+	}
 	return &stride{
-		cpu:     cpus[0],
+		cpus:    cpus,
 		quantum: quantum,
 		queue:   make(job.Jobs, 0),
-		job:     cpus[0].CurrentJob(),
 	}
 }
+
+// Add adds a new job to the queue; the job joins at the current global pass,
+// so that it neither monopolizes the CPU nor starves. A job created without
+// NewJob is given the stride corresponding to its tickets.
 func (s *stride) Add(job *job.Job) {
-	// TODO(student) Add job to queue
+	if job.Stride == 0 {
+		job.Stride = strideFor(job.Tickets)
+	}
+	job.Pass = s.globalPass
 	s.queue = append(s.queue, job)
 }
 
@@ -35,47 +65,76 @@ func (s *stride) Add(job *job.Job) {
 // the Tick method may assign new jobs to the CPU before returning.
 func (s *stride) Tick(systemTime time.Duration) int {
 	jobsFinished := 0
-	s.remaining -= systemTime * systime.TickDuration
-	done := false
-	if s.remaining <= 0 {
-		done = true
-		if done {
+	sliceExhausted := systemTime%s.quantum == 0
+	preempted := make([]bool, len(s.cpus))
+	for i, c := range s.cpus {
+		if c.IsRunning() && c.Tick() {
+			// finished jobs leave the system
 			jobsFinished++
-			s.reassign()
-			return jobsFinished
+		}
+		if sliceExhausted && c.IsRunning() {
+			s.queue = append(s.queue, c.CurrentJob())
+			preempted[i] = true
+		}
+	}
+	for i, c := range s.cpus {
+		if preempted[i] || !c.IsRunning() {
+			s.reassign(c)
 		}
 	}
-	// TODO(student) Implement Tick()
 	return jobsFinished
 }
 
-// reassign assigns a job to the cpu
-func (s *stride) reassign() {
-	// TODO(student) Implement reassign and use it from Tick()
-	s.cpu.Assign(s.getNewJob())
+// reassign assigns a job to the given CPU
+func (s *stride) reassign(c *cpu.CPU) {
+	c.Assign(s.getNewJob())
 }
 
-// getNewJob finds a new job to run on the CPU, removes the job from the queue and returns the job
+// getNewJob finds a new job to run on the CPU, removes the job from the queue and returns the job.
+// The job is charged a full stride up front, even if it only runs for part of
+// the quantum: when it finishes or blocks early, or when it is dispatched
+// to a CPU that went idle in the middle of a quantum. This approximation
+// only matters for the latter; finished jobs leave, and blocked jobs rejoin
+// at the global pass.
 func (s *stride) getNewJob() *job.Job {
-	// TODO(student) Implement getNewJob and use it from reassign
-	minimum := MinPass(s.queue) //current                  //pick client with min pass
-	s.queue[minimum].Pass += s.queue[minimum].Stride
-	return s.queue[minimum]
+	if len(s.queue) == 0 {
+		return nil
+	}
+	minimum := MinPass(s.queue) // pick client with min pass
+	removedJob := s.queue[minimum]
+	s.queue = append(s.queue[:minimum], s.queue[minimum+1:]...)
+	s.globalPass = removedJob.Pass
+	removedJob.Pass += removedJob.Stride // charge the job for the quantum it is given
+	return removedJob
 }
 
-// minPass returns the index of the job with the lowest pass value.
+// MinPass returns the index of the job with the lowest pass value;
+// ties are broken by the lowest stride, then by position in the queue.
 func MinPass(theJobs job.Jobs) int {
 	lowest := 0
 	for i := 0; i < len(theJobs); i++ {
-		if theJobs[lowest].Pass > theJobs[i].Pass { //if the lowest pass is greater than the jobs at i : starts at 0
-			lowest = i //assign lowest the lowest value
+		if theJobs[lowest].Pass > theJobs[i].Pass {
+			lowest = i
 			continue
 		}
 		if theJobs[lowest].Pass == theJobs[i].Pass && theJobs[lowest].Stride > theJobs[i].Stride {
 			lowest = i
 		}
 	}
-
-	// TODO(student) Implement MinPass and use it from getNewJob
 	return lowest
 }
+
+// NextDecision returns the next quantum boundary.
+func (s *stride) NextDecision(now time.Duration) time.Duration {
+	return (now/s.quantum + 1) * s.quantum
+}
+
+// Len returns the number of jobs waiting in the queue.
+func (s *stride) Len() int {
+	return len(s.queue)
+}
+
+// Running returns the jobs currently running on the CPUs.
+func (s *stride) Running() job.Jobs {
+	return cpu.Running(s.cpus)
+}
@@ -5,16 +5,24 @@ import (
 	"time"
 )
 
+// numerator is the large constant divided by a job's tickets to obtain its stride.
+const numerator = 10_000
+
 // NewJob creates a job for stride scheduling.
 func NewJob(size, tickets int, estimated time.Duration) *job.Job {
-	const numerator = 10_000
 	job := job.New(size, estimated) //creates new job with size and estimated duration
-	if tickets > 0 {                //no tickets
-		job.Tickets = tickets //assign tickets to job
-
+	if tickets > 0 {
+		job.Tickets = tickets
 	}
-	job.Stride = (int(estimated) / int(time.Nanosecond*1000)) / 100 // int(estimated)
-	// TODO(student) return the job with the correct fields
-
+	job.Stride = strideFor(job.Tickets)
 	return job
 }
+
+// strideFor returns the stride of a job holding the given number of tickets.
+// A job without tickets is given the stride of a single ticket.
+func strideFor(tickets int) int {
+	if tickets < 1 {
+		return numerator
+	}
+	return numerator / tickets
+}
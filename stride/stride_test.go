@@ -0,0 +1,79 @@
+package stride
+
+import (
+	"dat320/lab4/scheduler/cpu"
+	"dat320/lab4/scheduler/job"
+	"dat320/lab4/scheduler/system/systime"
+	"testing"
+	"time"
+)
+
+const ms = time.Millisecond
+
+// share runs the scheduler until the given time, and returns
+// the number of ticks each of the jobs was running on a CPU.
+func share(s *stride, clk *systime.ManualClock, until time.Duration, jobs job.Jobs) []int {
+	ticks := make([]int, len(jobs))
+	for ; clk.Time < until; clk.Time += ms {
+		for _, running := range s.Running() {
+			for i, j := range jobs {
+				if running == j {
+					ticks[i]++
+				}
+			}
+		}
+		s.Tick(clk.Time)
+	}
+	return ticks
+}
+
+func TestStrideFairness(t *testing.T) {
+	clk := &systime.ManualClock{}
+	s := New(cpu.NewCPUs(1), ms)
+	jobs := job.Jobs{NewJob(0, 100, time.Second), NewJob(0, 50, time.Second), NewJob(0, 250, time.Second)}
+	for _, j := range jobs {
+		j.Scheduled(clk)
+		s.Add(j)
+	}
+	ticks := share(s, clk, 401*ms, jobs)
+	want := []int{100, 50, 250}
+	for i := range jobs {
+		if ticks[i] < want[i]-1 || ticks[i] > want[i]+1 {
+			t.Errorf("job with %d tickets ran %d ticks, want %d", jobs[i].Tickets, ticks[i], want[i])
+		}
+	}
+
+	// a late arrival joins at the global pass, and gets its fair share
+	// instead of catching up on the time it was not in the system
+	late := NewJob(0, 100, time.Second)
+	late.Scheduled(clk)
+	s.Add(late)
+	jobs = append(jobs, late)
+	ticks = share(s, clk, 901*ms, jobs)
+	want = []int{100, 50, 250, 100}
+	for i := range jobs {
+		if ticks[i] < want[i]-2 || ticks[i] > want[i]+2 {
+			t.Errorf("after join: job with %d tickets ran %d ticks, want %d", jobs[i].Tickets, ticks[i], want[i])
+		}
+	}
+}
+
+func TestStrideJobsLeave(t *testing.T) {
+	clk := &systime.ManualClock{}
+	s := New(cpu.NewCPUs(2), 2*ms)
+	jobs := job.Jobs{NewJob(0, 100, 3*ms), NewJob(0, 50, 5*ms), NewJob(0, 250, 2*ms)}
+	for _, j := range jobs {
+		j.Scheduled(clk)
+		s.Add(j)
+	}
+	finished := 0
+	for ; finished < len(jobs) && clk.Time < time.Second; clk.Time += ms {
+		finished += s.Tick(clk.Time)
+	}
+	if finished != len(jobs) {
+		t.Fatalf("finished %d jobs, want %d", finished, len(jobs))
+	}
+	if s.Len() != 0 || len(s.Running()) != 0 {
+		t.Errorf("Len() = %d, Running() = %v; want no jobs left", s.Len(), s.Running())
+	}
+}
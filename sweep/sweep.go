@@ -0,0 +1,266 @@
+// Package sweep runs batches of scheduling simulations over a range of
+// parameters, to explore how quantum lengths and ticket distributions affect
+// the turnaround and response times of a workload.
+//
+// A sweep runs every combination of policy, quantum and, for policies that
+// use tickets, ticket distribution, in parallel goroutines:
+//
+//	points, err := sweep.Run(wl, sweep.Config{
+//		Policies: []string{"rr", "stride"},
+//		Quanta:   []time.Duration{time.Millisecond, 5 * time.Millisecond},
+//		Tickets:  []sweep.Distribution{sweep.Equal(100), sweep.Linear(100)},
+//	})
+//	sweep.WriteCSV(os.Stdout, points)
+//
+// The policies are looked up in the scheduler registry, so their packages
+// must be imported, for example with a blank import.
+package sweep
+
+import (
+	"dat320/lab4/scheduler"
+	"dat320/lab4/scheduler/cpu"
+	"dat320/lab4/scheduler/metrics"
+	"dat320/lab4/scheduler/system"
+	"dat320/lab4/scheduler/workload"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var (
+	errNoQuanta       = errors.New("invalid argument: at least one quantum is required")
+	errInvalidQuantum = errors.New("invalid argument: quanta must be greater than 0")
+	errNoCPUs         = errors.New("invalid argument: number of CPUs must not be negative")
+	errSwitchCost     = errors.New("invalid argument: context switch cost must not be negative")
+	errInvalidTickets = errors.New("invalid argument: ticket distribution must give every job a non-negative number of tickets")
+)
+
+// usesTickets holds the policies whose jobs are scheduled by their tickets;
+// only these are swept over ticket distributions.
+var usesTickets = map[string]bool{
+	"lottery": true,
+	"stride":  true,
+}
+
+// Distribution assigns tickets to the jobs of a workload.
+type Distribution struct {
+	Name    string
+	Tickets func(i int, spec workload.Spec) int // tickets of the i'th job
+}
+
+// Workload keeps the tickets given in the workload.
+var Workload = Distribution{
+	Name:    "workload",
+	Tickets: func(_ int, spec workload.Spec) int { return spec.Tickets },
+}
+
+// Equal gives every job the same number of tickets.
+func Equal(tickets int) Distribution {
+	return Distribution{
+		Name:    "equal",
+		Tickets: func(int, workload.Spec) int { return tickets },
+	}
+}
+
+// Linear gives the i'th job (i+1)*step tickets, so that later jobs get larger shares.
+func Linear(step int) Distribution {
+	return Distribution{
+		Name:    "linear",
+		Tickets: func(i int, _ workload.Spec) int { return (i + 1) * step },
+	}
+}
+
+// Inverse gives each job scale tickets divided by its estimated duration in
+// milliseconds, favoring short jobs. Every job gets at least one ticket.
+func Inverse(scale int) Distribution {
+	return Distribution{
+		Name: "inverse",
+		Tickets: func(_ int, spec workload.Spec) int {
+			if t := int(int64(scale) * int64(time.Millisecond) / int64(spec.Estimated)); t > 0 {
+				return t
+			}
+			return 1
+		},
+	}
+}
+
+// validate returns an error unless the distribution gives every job of the
+// workload a non-negative number of tickets.
+func (d Distribution) validate(wl workload.Workload) error {
+	if d.Tickets == nil {
+		return fmt.Errorf("%q: %w", d.Name, errInvalidTickets)
+	}
+	for i, spec := range wl {
+		if d.Tickets(i, spec) < 0 {
+			return fmt.Errorf("%q: %w", d.Name, errInvalidTickets)
+		}
+	}
+	return nil
+}
+
+// apply returns a copy of the workload with tickets assigned by the distribution.
+func (d Distribution) apply(wl workload.Workload) workload.Workload {
+	specs := make(workload.Workload, len(wl))
+	for i, spec := range wl {
+		spec.Tickets = d.Tickets(i, spec)
+		specs[i] = spec
+	}
+	return specs
+}
+
+// Config describes a sweep.
+type Config struct {
+	Policies []string
+	Quanta   []time.Duration
+	Tickets  []Distribution    // distributions for policies using tickets; nil keeps the workload's tickets
+	CPUs     int               // number of CPUs; zero means one
+	Workers  int               // maximum number of parallel simulations; zero means GOMAXPROCS
+	Options  scheduler.Options // options of the policies, including the switch cost; the quantum is set by the sweep
+}
+
+// Point is the result of one simulation of a sweep.
+type Point struct {
+	Policy  string
+	Quantum time.Duration
+	Tickets string // name of the ticket distribution; empty for policies without tickets
+	Report  metrics.Report
+}
+
+// run is a single simulation of a sweep.
+type run struct {
+	policy  string
+	quantum time.Duration
+	tickets Distribution
+}
+
+// Run runs the simulations of the sweep in parallel, and returns their
+// results in the order of the policies, quanta and distributions in the config.
+// If any simulation fails, Run returns the error of the first failing one.
+// Run returns an error without running any simulation if the config is invalid.
+func Run(wl workload.Workload, cfg Config) ([]Point, error) {
+	if len(cfg.Quanta) == 0 {
+		return nil, errNoQuanta
+	}
+	for _, q := range cfg.Quanta {
+		if q <= 0 {
+			return nil, fmt.Errorf("%v: %w", q, errInvalidQuantum)
+		}
+	}
+	if cfg.CPUs < 0 {
+		return nil, errNoCPUs
+	}
+	if cfg.Options.SwitchCost < 0 {
+		return nil, errSwitchCost
+	}
+	if cfg.CPUs == 0 {
+		cfg.CPUs = 1
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = runtime.GOMAXPROCS(0)
+	}
+	dists := cfg.Tickets
+	if len(dists) == 0 {
+		dists = []Distribution{Workload}
+	}
+	for _, d := range dists {
+		if err := d.validate(wl); err != nil {
+			return nil, err
+		}
+	}
+	var runs []run
+	for _, policy := range cfg.Policies {
+		for _, q := range cfg.Quanta {
+			if !usesTickets[policy] {
+				runs = append(runs, run{policy: policy, quantum: q})
+				continue
+			}
+			for _, d := range dists {
+				runs = append(runs, run{policy: policy, quantum: q, tickets: d})
+			}
+		}
+	}
+
+	points := make([]Point, len(runs))
+	errs := make([]error, len(runs))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < cfg.Workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				points[i], errs[i] = simulate(wl, runs[i], cfg)
+			}
+		}()
+	}
+	for i := range runs {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("%s with quantum %v: %w", runs[i].policy, runs[i].quantum, err)
+		}
+	}
+	return points, nil
+}
+
+// simulate runs a fresh copy of the workload for a single run of the sweep.
+func simulate(wl workload.Workload, r run, cfg Config) (Point, error) {
+	if r.tickets.Tickets != nil {
+		wl = r.tickets.apply(wl)
+	}
+	schedule := wl.Schedule()
+	cpus := cpu.NewCPUs(cfg.CPUs)
+	opts := cfg.Options
+	opts.Quantum = r.quantum
+	sched, err := scheduler.New(r.policy, cpus, opts)
+	if err != nil {
+		return Point{}, err
+	}
+	jobs, err := system.Run(sched, schedule)
+	if err != nil {
+		return Point{}, err
+	}
+	return Point{
+		Policy:  r.policy,
+		Quantum: r.quantum,
+		Tickets: r.tickets.Name,
+		Report:  metrics.Summarize(jobs, cpus),
+	}, nil
+}
+
+// WriteCSV writes the points as CSV, with a header line and one line per point.
+// Durations are written in milliseconds.
+func WriteCSV(w io.Writer, points []Point) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{
+		"policy", "quantum_ms", "tickets", "jobs",
+		"turnaround_mean_ms", "turnaround_p95_ms",
+		"response_mean_ms", "response_p95_ms",
+		"waiting_mean_ms", "fairness", "preemptions",
+	})
+	for _, p := range points {
+		r := p.Report
+		cw.Write([]string{
+			p.Policy, millis(p.Quantum), p.Tickets, strconv.Itoa(r.Jobs),
+			millis(r.Turnaround.Mean), millis(r.Turnaround.P95),
+			millis(r.Response.Mean), millis(r.Response.P95),
+			millis(r.Waiting.Mean), strconv.FormatFloat(r.Fairness, 'f', 3, 64), strconv.Itoa(r.Preemptions),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// millis formats the duration as a number of milliseconds.
+func millis(d time.Duration) string {
+	return strconv.FormatFloat(float64(d)/float64(time.Millisecond), 'f', -1, 64)
+}
@@ -0,0 +1,125 @@
+package sweep
+
+import (
+	"bytes"
+	"dat320/lab4/scheduler"
+	"dat320/lab4/scheduler/workload"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	_ "dat320/lab4/scheduler/rr"
+	_ "dat320/lab4/scheduler/stride"
+)
+
+const ms = time.Millisecond
+
+var wl = workload.Workload{
+	{Arrival: 0, Estimated: 30 * ms, Tickets: 100},
+	{Arrival: 0, Estimated: 10 * ms, Tickets: 100},
+	{Arrival: 5 * ms, Estimated: 5 * ms, Tickets: 100},
+}
+
+func TestRun(t *testing.T) {
+	cfg := Config{
+		Policies: []string{"rr", "stride"},
+		Quanta:   []time.Duration{1 * ms, 5 * ms},
+		Tickets:  []Distribution{Equal(100), Inverse(1000)},
+	}
+	points, err := Run(wl, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		policy  string
+		quantum time.Duration
+		tickets string
+	}{
+		{"rr", 1 * ms, ""},
+		{"rr", 5 * ms, ""},
+		{"stride", 1 * ms, "equal"},
+		{"stride", 1 * ms, "inverse"},
+		{"stride", 5 * ms, "equal"},
+		{"stride", 5 * ms, "inverse"},
+	}
+	if len(points) != len(want) {
+		t.Fatalf("Run() returned %d points, want %d", len(points), len(want))
+	}
+	for i, w := range want {
+		p := points[i]
+		if p.Policy != w.policy || p.Quantum != w.quantum || p.Tickets != w.tickets {
+			t.Errorf("Run()[%d] = %s/%v/%s, want %s/%v/%s", i, p.Policy, p.Quantum, p.Tickets, w.policy, w.quantum, w.tickets)
+		}
+		if p.Report.Jobs != len(wl) {
+			t.Errorf("Run()[%d]: %d jobs finished, want %d", i, p.Report.Jobs, len(wl))
+		}
+	}
+	// favoring the short jobs lowers the mean turnaround time
+	if equal, inverse := points[2].Report.Turnaround.Mean, points[3].Report.Turnaround.Mean; inverse >= equal {
+		t.Errorf("stride turnaround with inverse tickets = %v, want less than %v with equal tickets", inverse, equal)
+	}
+
+	cfg.Workers = 1
+	sequential, err := Run(wl, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(sequential, points) {
+		t.Errorf("Run() with one worker differs from parallel run")
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, points); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != len(points)+1 || !strings.HasPrefix(lines[1], "rr,1,,3,") {
+		t.Errorf("WriteCSV() =\n%s", buf.String())
+	}
+}
+
+func TestRunCaches(t *testing.T) {
+	cfg := Config{Policies: []string{"rr"}, Quanta: []time.Duration{5 * ms}}
+	plain, err := Run(wl, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Options = scheduler.Options{CacheSize: 4, CacheWarmup: 2 * ms, WarmSpeed: 2}
+	cached, err := Run(wl, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// warm caches speed up the jobs
+	if got, max := cached[0].Report.Turnaround.Mean, plain[0].Report.Turnaround.Mean; got >= max {
+		t.Errorf("turnaround with caches = %v, want less than %v without", got, max)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	if _, err := Run(wl, Config{Policies: []string{"rr"}}); err == nil {
+		t.Errorf("Run() without quanta: got nil error")
+	}
+	if _, err := Run(wl, Config{Policies: []string{"nosuchpolicy"}, Quanta: []time.Duration{ms}}); err == nil {
+		t.Errorf("Run() with unknown policy: got nil error")
+	}
+	quanta := []time.Duration{ms}
+	tests := []struct {
+		name string
+		cfg  Config
+		want error
+	}{
+		{"negative switch cost", Config{Quanta: quanta, Options: scheduler.Options{SwitchCost: -ms}}, errSwitchCost},
+		{"negative CPUs", Config{Quanta: quanta, CPUs: -1}, errNoCPUs},
+		{"zero quantum", Config{Quanta: []time.Duration{ms, 0}}, errInvalidQuantum},
+		{"no ticket function", Config{Quanta: quanta, Tickets: []Distribution{{Name: "none"}}}, errInvalidTickets},
+		{"negative tickets", Config{Quanta: quanta, Tickets: []Distribution{Linear(-1)}}, errInvalidTickets},
+	}
+	for _, test := range tests {
+		test.cfg.Policies = []string{"rr", "stride"}
+		if _, err := Run(wl, test.cfg); !errors.Is(err, test.want) {
+			t.Errorf("Run() with %s: got %v, want %v", test.name, err, test.want)
+		}
+	}
+}
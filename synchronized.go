@@ -0,0 +1,66 @@
+package scheduler
+
+import (
+	"dat320/lab4/scheduler/job"
+	"sync"
+	"time"
+)
+
+var _ Blocker = (*synchronized)(nil)
+
+// synchronized guards a scheduler with a mutex.
+type synchronized struct {
+	mu    sync.Mutex
+	sched Scheduler
+}
+
+// Synchronized returns a scheduler that serializes all calls to s, so that
+// it can be used from several goroutines; for example, to add jobs or monitor
+// the queue from other goroutines while a simulation runs. The returned
+// scheduler is a Blocker: if s is not, Block has no effect and Unblock adds
+// the job, as the simulation does for schedulers that are not Blockers.
+func Synchronized(s Scheduler) Scheduler {
+	return &synchronized{sched: s}
+}
+
+func (s *synchronized) Add(j *job.Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sched.Add(j)
+}
+
+func (s *synchronized) Tick(systemTime time.Duration) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sched.Tick(systemTime)
+}
+
+func (s *synchronized) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sched.Len()
+}
+
+func (s *synchronized) Running() job.Jobs {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sched.Running()
+}
+
+func (s *synchronized) Block(j *job.Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if b, ok := s.sched.(Blocker); ok {
+		b.Block(j)
+	}
+}
+
+func (s *synchronized) Unblock(j *job.Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if b, ok := s.sched.(Blocker); ok {
+		b.Unblock(j)
+		return
+	}
+	s.sched.Add(j)
+}
@@ -0,0 +1,28 @@
+package system
+
+import (
+	"dat320/lab4/scheduler/system/systime"
+	"time"
+)
+
+// Clock is a simulated clock that advances one tick at a time.
+type Clock struct {
+	now time.Duration
+}
+
+var _ systime.SystemTime = (*Clock)(nil)
+
+// Now returns the current simulated time.
+func (c *Clock) Now() time.Duration {
+	return c.now
+}
+
+// Tick advances the clock by one tick.
+func (c *Clock) Tick() {
+	c.now += systime.TickDuration
+}
+
+// advance advances the clock by n ticks.
+func (c *Clock) advance(n int) {
+	c.now += time.Duration(n) * systime.TickDuration
+}
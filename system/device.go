@@ -0,0 +1,58 @@
+package system
+
+import (
+	"dat320/lab4/scheduler/job"
+	"math"
+)
+
+// Device simulates an I/O device that serves all outstanding requests
+// concurrently; each blocked job is woken when its own I/O burst completes.
+type Device struct {
+	pending job.Jobs
+}
+
+// Submit starts the I/O of a blocked job.
+func (d *Device) Submit(j *job.Job) {
+	d.pending = append(d.pending, j)
+}
+
+// Tick advances the I/O of all pending jobs by one tick, and returns
+// the jobs whose I/O completed, in the order they were submitted.
+func (d *Device) Tick() job.Jobs {
+	var woken job.Jobs
+	pending := d.pending[:0]
+	for _, j := range d.pending {
+		if j.IOTick() {
+			woken = append(woken, j)
+		} else {
+			pending = append(pending, j)
+		}
+	}
+	d.pending = pending
+	return woken
+}
+
+// Len returns the number of jobs waiting for I/O.
+func (d *Device) Len() int {
+	return len(d.pending)
+}
+
+// Horizon returns the number of ticks until the I/O of the first pending job
+// completes, or math.MaxInt if no jobs are waiting for I/O.
+func (d *Device) Horizon() int {
+	horizon := math.MaxInt
+	for _, j := range d.pending {
+		if left := j.IOTicksLeft(); left < horizon {
+			horizon = left
+		}
+	}
+	return horizon
+}
+
+// Advance advances the I/O of all pending jobs by n ticks at once, as n
+// calls to Tick would. Advance panics unless n is less than the Horizon.
+func (d *Device) Advance(n int) {
+	for _, j := range d.pending {
+		j.IOAdvance(n)
+	}
+}
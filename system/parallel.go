@@ -0,0 +1,75 @@
+package system
+
+import (
+	"dat320/lab4/scheduler/cpu"
+	"dat320/lab4/scheduler/job"
+	"errors"
+	"sync"
+)
+
+var errSharedCache = errors.New("CPUs sharing a cache cannot run in parallel")
+
+// RunParallel is like Run, but runs each of the scheduler's CPUs in its own
+// goroutine. Every tick is split in two phases separated by a barrier: first,
+// all CPUs run their current job for the tick in parallel (see cpu.CPU.Step);
+// then, once every CPU has reached the barrier, the scheduler's Tick method
+// collects the results and makes its scheduling decisions sequentially.
+// Since CPUs only touch their own job and cache during the parallel phase,
+// the simulation is deterministic and gives the same results as Run.
+//
+// The CPUs must be those of the scheduler, and must not share a cache;
+// the caches attached by cpu.SetCaches are separate, and may be used.
+// Job observers are notified from the CPU goroutines, and must be safe for
+// concurrent use; the order of events from different CPUs within a tick
+// may vary between runs. To use the scheduler from other goroutines while
+// the simulation runs, wrap it with scheduler.Synchronized.
+func (s *System) RunParallel(cpus []*cpu.CPU) (job.Jobs, error) {
+	caches := make(map[*cpu.Cache]bool)
+	for _, c := range cpus {
+		if cache := c.Cache(); cache != nil {
+			if caches[cache] {
+				return nil, errSharedCache
+			}
+			caches[cache] = true
+		}
+	}
+	b := startBarrier(cpus)
+	defer b.stop()
+	return s.run(engine{step: b.step})
+}
+
+// barrier runs a goroutine per CPU, and steps all CPUs in parallel.
+type barrier struct {
+	start []chan struct{} // one per CPU goroutine
+	done  sync.WaitGroup
+}
+
+func startBarrier(cpus []*cpu.CPU) *barrier {
+	b := &barrier{start: make([]chan struct{}, len(cpus))}
+	for i, c := range cpus {
+		b.start[i] = make(chan struct{})
+		go func(c *cpu.CPU, start <-chan struct{}) {
+			for range start {
+				c.Step()
+				b.done.Done()
+			}
+		}(c, b.start[i])
+	}
+	return b
+}
+
+// step steps every CPU once, and waits until all CPUs are done.
+func (b *barrier) step() {
+	b.done.Add(len(b.start))
+	for _, start := range b.start {
+		start <- struct{}{}
+	}
+	b.done.Wait()
+}
+
+// stop stops the CPU goroutines.
+func (b *barrier) stop() {
+	for _, start := range b.start {
+		close(start)
+	}
+}
@@ -0,0 +1,172 @@
+// Package system provides a deterministic simulation driver that delivers
+// jobs to a scheduler at their arrival times and ticks the scheduler
+// until all jobs have finished. The simulation runs one tick at a time,
+// with each CPU in its own goroutine (see System.RunParallel), or from
+// event to event (see System.RunTickless).
+package system
+
+import (
+	"dat320/lab4/scheduler"
+	"dat320/lab4/scheduler/job"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+var errJobsLost = errors.New("scheduler has no jobs left to run, but not all jobs have finished")
+
+// Entry is a job and the time at which it arrives in the system.
+type Entry struct {
+	Job     *job.Job
+	Arrival time.Duration
+}
+
+// Schedule is a list of jobs and their arrival times.
+type Schedule []*Entry
+
+// Jobs returns the jobs in the schedule.
+func (s Schedule) Jobs() job.Jobs {
+	jobs := make(job.Jobs, len(s))
+	for i, entry := range s {
+		jobs[i] = entry.Job
+	}
+	return jobs
+}
+
+// System runs a schedule of jobs on a scheduler.
+type System struct {
+	clock    *Clock
+	sched    scheduler.Scheduler
+	schedule Schedule // ordered by arrival time
+	device   *Device
+	onTick   []func(now time.Duration)
+}
+
+// New returns a system that will run the scheduled jobs on the given scheduler.
+func New(sched scheduler.Scheduler, schedule Schedule) *System {
+	ordered := make(Schedule, len(schedule))
+	copy(ordered, schedule)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Arrival < ordered[j].Arrival })
+	return &System{
+		clock:    &Clock{},
+		sched:    sched,
+		schedule: ordered,
+		device:   &Device{},
+	}
+}
+
+// Now returns the current simulated time.
+func (s *System) Now() time.Duration {
+	return s.clock.Now()
+}
+
+// OnTick registers a function to be called at every tick, after the jobs
+// arriving at that time have been added to the scheduler, and before the
+// scheduler's Tick method runs the jobs on the CPUs.
+func (s *System) OnTick(fn func(now time.Duration)) {
+	s.onTick = append(s.onTick, fn)
+}
+
+// Observe subscribes the observer to the lifecycle events of all scheduled
+// jobs. Observe must be called before Run.
+func (s *System) Observe(o job.Observer) {
+	for _, entry := range s.schedule {
+		entry.Job.Subscribe(o)
+	}
+}
+
+// Run delivers each job to the scheduler at its arrival time, and ticks
+// the scheduler until all jobs have finished. Jobs that block for I/O are
+// handed to the system's I/O device, and given back to the scheduler when
+// their I/O completes. Run returns the finished jobs ordered by arrival time.
+// Run returns an error if the scheduler runs out of jobs before all
+// delivered jobs have finished.
+func (s *System) Run() (job.Jobs, error) {
+	return s.run(engine{})
+}
+
+// engine customizes how a simulation advances; the zero engine
+// runs the simulation one tick at a time.
+type engine struct {
+	// step, if not nil, is called at every tick just before the
+	// scheduler's Tick method, to run the CPUs ahead of it.
+	step func()
+	// skip, if not nil, is called at the end of every tick, and returns the
+	// number of following ticks that it advanced the simulation over.
+	skip func(now, nextArrival time.Duration) int
+}
+
+// run runs the simulation using the given engine; see Run.
+func (s *System) run(e engine) (job.Jobs, error) {
+	next, finished := 0, 0
+	for finished < len(s.schedule) {
+		now := s.clock.Now()
+		for ; next < len(s.schedule) && s.schedule[next].Arrival <= now; next++ {
+			entry := s.schedule[next]
+			entry.Job.Scheduled(s.clock)
+			s.sched.Add(entry.Job)
+		}
+		for _, j := range s.device.Tick() {
+			s.unblock(j)
+		}
+		for _, fn := range s.onTick {
+			fn(now)
+		}
+		running := s.sched.Running()
+		if e.step != nil {
+			e.step()
+		}
+		finished += s.sched.Tick(now)
+		for _, j := range running {
+			if j.IsBlocked() {
+				s.block(j)
+			}
+		}
+		if finished < next && s.idle() {
+			return nil, fmt.Errorf("at %v: %w", now, errJobsLost)
+		}
+		if e.skip != nil && finished < len(s.schedule) {
+			s.clock.advance(e.skip(now, s.nextArrival(next)))
+		}
+		s.clock.Tick()
+	}
+	return s.schedule.Jobs(), nil
+}
+
+// nextArrival returns the arrival time of the next job to be delivered,
+// or scheduler.Never if all jobs have been delivered.
+func (s *System) nextArrival(next int) time.Duration {
+	if next < len(s.schedule) {
+		return s.schedule[next].Arrival
+	}
+	return scheduler.Never
+}
+
+// block notifies the scheduler that the job blocked, and starts its I/O.
+func (s *System) block(j *job.Job) {
+	if b, ok := s.sched.(scheduler.Blocker); ok {
+		b.Block(j)
+	}
+	s.device.Submit(j)
+}
+
+// unblock gives a job whose I/O completed back to the scheduler.
+func (s *System) unblock(j *job.Job) {
+	if b, ok := s.sched.(scheduler.Blocker); ok {
+		b.Unblock(j)
+		return
+	}
+	s.sched.Add(j)
+}
+
+// idle returns true if the scheduler has no queued or running jobs,
+// and no jobs are waiting for I/O.
+func (s *System) idle() bool {
+	return s.sched.Len() == 0 && len(s.sched.Running()) == 0 && s.device.Len() == 0
+}
+
+// Run runs the scheduled jobs on the given scheduler; see System.Run.
+func Run(sched scheduler.Scheduler, schedule Schedule) (job.Jobs, error) {
+	return New(sched, schedule).Run()
+}
@@ -0,0 +1,258 @@
+package system
+
+import (
+	"dat320/lab4/scheduler"
+	"dat320/lab4/scheduler/cpu"
+	"dat320/lab4/scheduler/fifo"
+	"dat320/lab4/scheduler/job"
+	"dat320/lab4/scheduler/timeline"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	_ "dat320/lab4/scheduler/cfs"
+	_ "dat320/lab4/scheduler/lottery"
+	_ "dat320/lab4/scheduler/mlfq"
+	_ "dat320/lab4/scheduler/rr"
+	_ "dat320/lab4/scheduler/sjf"
+	_ "dat320/lab4/scheduler/steal"
+	_ "dat320/lab4/scheduler/stride"
+)
+
+const ms = time.Millisecond
+
+func TestRun(t *testing.T) {
+	// deliberately out of order; Run delivers jobs by arrival time
+	schedule := Schedule{
+		{Job: job.New(0, 2*ms), Arrival: 5 * ms},
+		{Job: job.New(0, 3*ms), Arrival: 0},
+	}
+	jobs, err := Run(fifo.New(cpu.NewCPUs(1)), schedule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 2 || jobs[0] != schedule[1].Job || jobs[1] != schedule[0].Job {
+		t.Fatalf("Run() = %v, want jobs ordered by arrival", jobs)
+	}
+	wantTurnaround := []time.Duration{3 * ms, 2 * ms}
+	for i, j := range jobs {
+		if got := j.TurnaroundTime(); got != wantTurnaround[i] {
+			t.Errorf("job %s: TurnaroundTime() = %v, want %v", j.Name(), got, wantTurnaround[i])
+		}
+	}
+}
+
+// lossy is a scheduler that forgets all jobs added to it.
+type lossy struct{}
+
+func (lossy) Add(*job.Job)           {}
+func (lossy) Tick(time.Duration) int { return 0 }
+func (lossy) Len() int               { return 0 }
+func (lossy) Running() job.Jobs      { return nil }
+
+func TestRunJobsLost(t *testing.T) {
+	schedule := Schedule{{Job: job.New(0, 2*ms), Arrival: 1 * ms}}
+	if _, err := Run(lossy{}, schedule); !errors.Is(err, errJobsLost) {
+		t.Errorf("Run(lossy) error = %v, want %v", err, errJobsLost)
+	}
+}
+
+func TestRunIO(t *testing.T) {
+	// A blocks for 3ms after 2ms; B runs in the meantime
+	a, b := job.NewIO(0, 2*ms, 3*ms, 1*ms), job.New(0, 4*ms)
+	schedule := Schedule{{Job: a, Arrival: 0}, {Job: b, Arrival: 0}}
+	if _, err := Run(fifo.New(cpu.NewCPUs(1)), schedule); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name                      string
+		j                         *job.Job
+		turnaround, waiting, runs time.Duration
+	}{
+		{"A", a, 7 * ms, 1 * ms, 3 * ms},
+		{"B", b, 6 * ms, 2 * ms, 4 * ms},
+	}
+	for _, test := range tests {
+		if got := test.j.TurnaroundTime(); got != test.turnaround {
+			t.Errorf("%s: TurnaroundTime() = %v, want %v", test.name, got, test.turnaround)
+		}
+		if got := test.j.WaitingTime(); got != test.waiting {
+			t.Errorf("%s: WaitingTime() = %v, want %v", test.name, got, test.waiting)
+		}
+		if got := test.j.RunTime(); got != test.runs {
+			t.Errorf("%s: RunTime() = %v, want %v", test.name, got, test.runs)
+		}
+	}
+	if a.Blockings() != 1 || a.IsBlocked() {
+		t.Errorf("A: Blockings() = %d, IsBlocked() = %t; want 1, false", a.Blockings(), a.IsBlocked())
+	}
+}
+
+// outcome is what a simulation determines about a job.
+type outcome struct {
+	turnaround, response, waiting, runTime time.Duration
+	switches                               int
+}
+
+// result is what simulate observed of a simulation.
+type result struct {
+	outcomes []outcome
+	busy     []time.Duration // busy and switching time of each CPU
+	segments []timeline.Segment
+	ticks    int // number of ticks simulated one at a time
+}
+
+// simulation describes a simulation run by simulate.
+type simulation struct {
+	cpus     int               // number of CPUs
+	opts     scheduler.Options // options of the policy, and of the CPUs' switch costs and caches
+	setup    func([]*cpu.CPU)  // if not nil, configures the CPUs before the scheduler is constructed
+	schedule func() Schedule   // returns new jobs for every run
+}
+
+// sequential runs the system one tick at a time; see System.Run.
+func sequential(s *System, _ []*cpu.CPU) (job.Jobs, error) {
+	return s.Run()
+}
+
+// simulate runs the simulation with the named policy on new CPUs using
+// the given engine, e.g. sequential or (*System).RunParallel, and returns
+// the jobs' outcomes, the CPUs' busy and switching times, and the timeline.
+func simulate(t *testing.T, sim simulation, policy string, engine func(*System, []*cpu.CPU) (job.Jobs, error)) result {
+	t.Helper()
+	cpus := cpu.NewCPUs(sim.cpus)
+	if sim.setup != nil {
+		sim.setup(cpus)
+	}
+	sched, err := scheduler.New(policy, cpus, sim.opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sys := New(sched, sim.schedule())
+	rec := timeline.New(cpus)
+	sys.OnTick(rec.Record)
+	var r result
+	sys.OnTick(func(time.Duration) { r.ticks++ })
+	finished, err := engine(sys, cpus)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, j := range finished {
+		r.outcomes = append(r.outcomes, outcome{j.TurnaroundTime(), j.ResponseTime(), j.WaitingTime(), j.RunTime(), j.ContextSwitches()})
+	}
+	for _, c := range cpus {
+		r.busy = append(r.busy, c.BusyTime(), c.SwitchTime())
+	}
+	r.segments = rec.Segments()
+	return r
+}
+
+func TestRunParallel(t *testing.T) {
+	// a mix of CPU-bound and I/O-bound jobs on three CPUs with context
+	// switch costs and a cache each, which evict finished jobs from each
+	// other if shared is true
+	newSimulation := func(shared bool) simulation {
+		sim := simulation{
+			cpus: 3,
+			opts: scheduler.Options{Quantum: 2 * ms, BoostPeriod: 20 * ms, Seed: 1, SwitchCost: 1 * ms},
+			schedule: func() Schedule {
+				jobs := job.NewFactory()
+				var schedule Schedule
+				for i := 0; i < 24; i++ {
+					j := jobs.New(1+i%2, time.Duration(1+i%7)*ms)
+					if i%4 == 0 {
+						j = jobs.NewIO(1, 2*ms, time.Duration(1+i%3)*ms, 3*ms)
+					}
+					schedule = append(schedule, &Entry{Job: j, Arrival: time.Duration(i/2) * ms})
+				}
+				return schedule
+			},
+		}
+		if shared {
+			sim.opts.CacheSize, sim.opts.CacheWarmup, sim.opts.WarmSpeed = 4, 2*ms, 2
+		} else {
+			sim.setup = func(cpus []*cpu.CPU) {
+				for _, c := range cpus {
+					c.SetCache(cpu.NewCache(4, 2*ms, 2))
+				}
+			}
+		}
+		return sim
+	}
+	for _, policy := range []string{"fifo", "rr", "steal", "cfs", "mlfq", "lottery"} {
+		t.Run(policy, func(t *testing.T) {
+			for _, shared := range []bool{false, true} {
+				sim := newSimulation(shared)
+				want := simulate(t, sim, policy, sequential)
+				for i := 0; i < 3; i++ {
+					if got := simulate(t, sim, policy, (*System).RunParallel); !reflect.DeepEqual(got, want) {
+						t.Fatalf("RunParallel() with shared caches %t =\n%+v\nwant as Run()\n%+v", shared, got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRunParallelSharedCache(t *testing.T) {
+	cpus := cpu.NewCPUs(2)
+	cache := cpu.NewCache(4, 2*ms, 2)
+	cpus[0].SetCache(cache)
+	cpus[1].SetCache(cache)
+	sys := New(fifo.New(cpus), Schedule{{Job: job.New(0, 1*ms), Arrival: 0}})
+	if _, err := sys.RunParallel(cpus); !errors.Is(err, errSharedCache) {
+		t.Errorf("RunParallel() error = %v, want %v", err, errSharedCache)
+	}
+}
+
+func TestRunTickless(t *testing.T) {
+	// long CPU-bound and I/O-bound jobs on two CPUs with a context switch cost
+	sim := simulation{
+		cpus: 2,
+		opts: scheduler.Options{Quantum: 5 * ms, SwitchCost: 1 * ms},
+		schedule: func() Schedule {
+			jobs := job.NewFactory()
+			var schedule Schedule
+			for i := 0; i < 12; i++ {
+				j := jobs.New(0, time.Duration(20+37*i%90)*ms)
+				if i%3 == 0 {
+					j = jobs.NewIO(0, time.Duration(10+i)*ms, 15*ms, 25*ms)
+				}
+				j.Tickets = 100 * (1 + i%3)
+				schedule = append(schedule, &Entry{Job: j, Arrival: time.Duration(17*i) * ms})
+			}
+			return schedule
+		},
+	}
+	for _, policy := range []string{"fifo", "sjf", "stcf", "rr", "stride"} {
+		t.Run(policy, func(t *testing.T) {
+			want := simulate(t, sim, policy, sequential)
+			got := simulate(t, sim, policy, (*System).RunTickless)
+			if !reflect.DeepEqual(got.outcomes, want.outcomes) {
+				t.Errorf("RunTickless() outcomes =\n%v\nwant as Run()\n%v", got.outcomes, want.outcomes)
+			}
+			if !reflect.DeepEqual(got.busy, want.busy) {
+				t.Errorf("RunTickless() busy and switching times = %v, want %v", got.busy, want.busy)
+			}
+			if !reflect.DeepEqual(got.segments, want.segments) {
+				t.Errorf("RunTickless() timeline =\n%v\nwant\n%v", got.segments, want.segments)
+			}
+			if got.ticks >= want.ticks/2 {
+				t.Errorf("RunTickless() simulated %d of %d ticks, want less than half", got.ticks, want.ticks)
+			}
+		})
+	}
+
+	// CPUs with a cache are simulated one tick at a time while they run a job
+	t.Run("cache", func(t *testing.T) {
+		cached := sim
+		cached.opts.CacheSize, cached.opts.CacheWarmup, cached.opts.WarmSpeed = 4, 2*ms, 2
+		want := simulate(t, cached, "rr", sequential)
+		got := simulate(t, cached, "rr", (*System).RunTickless)
+		got.ticks, want.ticks = 0, 0
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("RunTickless() with caches = %+v, want %+v", got, want)
+		}
+	})
+}
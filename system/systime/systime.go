@@ -0,0 +1,23 @@
+// Package systime defines the simulated system time shared by jobs and schedulers.
+package systime
+
+import "time"
+
+// TickDuration is the amount of simulated time that passes in one clock tick.
+const TickDuration = time.Millisecond
+
+// SystemTime provides the current simulated time.
+type SystemTime interface {
+	Now() time.Duration
+}
+
+// ManualClock is a SystemTime whose time is only changed by setting it.
+// It drives jobs and schedulers outside of a simulation, such as in tests.
+type ManualClock struct {
+	Time time.Duration
+}
+
+// Now returns the time the clock was last set to.
+func (c *ManualClock) Now() time.Duration {
+	return c.Time
+}
@@ -0,0 +1,65 @@
+package system
+
+import (
+	"dat320/lab4/scheduler"
+	"dat320/lab4/scheduler/cpu"
+	"dat320/lab4/scheduler/job"
+	"dat320/lab4/scheduler/system/systime"
+	"time"
+)
+
+// RunTickless is like Run, but jumps directly from one event to the next:
+// a job arriving, finishing, blocking for I/O or completing its I/O, and
+// the scheduler's next decision, such as the end of a quantum. The ticks in
+// between, in which the CPUs only run their jobs, are simulated at once (see
+// cpu.CPU.Advance), and the scheduler's Tick method and the functions
+// registered with OnTick are not called for them. The results are the same
+// as those of Run.
+//
+// The CPUs must be those of the scheduler. Schedulers that do not implement
+// scheduler.Tickless are simulated one tick at a time, as are all CPUs
+// while a CPU with a cache runs a job.
+func (s *System) RunTickless(cpus []*cpu.CPU) (job.Jobs, error) {
+	t, ok := s.sched.(scheduler.Tickless)
+	if !ok {
+		return s.Run()
+	}
+	return s.run(engine{skip: func(now, nextArrival time.Duration) int {
+		return s.skip(t, cpus, now, nextArrival)
+	}})
+}
+
+// skip advances the CPUs and the I/O device over the ticks after now
+// until the tick of the next event, and returns the number of ticks skipped.
+func (s *System) skip(t scheduler.Tickless, cpus []*cpu.CPU, now, nextArrival time.Duration) int {
+	horizon := ticksUntil(now, nextArrival)
+	if s.sched.Len() > 0 || len(s.sched.Running()) > 0 {
+		// a scheduler without jobs has no decisions to make
+		if h := ticksUntil(now, t.NextDecision(now)); h < horizon {
+			horizon = h
+		}
+	}
+	for _, c := range cpus {
+		if h := c.Horizon(); h < horizon {
+			horizon = h
+		}
+	}
+	if h := s.device.Horizon(); h < horizon {
+		horizon = h
+	}
+	// the event happens in the tick at the horizon, which must be simulated
+	n := horizon - 1
+	if n <= 0 {
+		return 0
+	}
+	for _, c := range cpus {
+		c.Advance(n)
+	}
+	s.device.Advance(n)
+	return n
+}
+
+// ticksUntil returns the number of ticks from now until the given time.
+func ticksUntil(now, t time.Duration) int {
+	return int((t - now) / systime.TickDuration)
+}
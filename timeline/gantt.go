@@ -0,0 +1,50 @@
+package timeline
+
+import (
+	"dat320/lab4/scheduler/system/systime"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const idleCell = "."
+
+// WriteGantt writes the recording as an ASCII Gantt chart with one row per
+// CPU and one column per tick, showing the name of the running job or a
+// dot if the CPU was idle.
+func (r *Recorder) WriteGantt(w io.Writer) error {
+	ticks := int(r.end / systime.TickDuration)
+	width := len(idleCell)
+	for _, seg := range r.segments {
+		if len(seg.Job) > width {
+			width = len(seg.Job)
+		}
+	}
+	rows := make([][]string, len(r.cpus))
+	for i := range rows {
+		rows[i] = make([]string, ticks)
+		for t := range rows[i] {
+			rows[i][t] = pad(idleCell, width)
+		}
+	}
+	index := make(map[int]int, len(r.cpus))
+	for i, c := range r.cpus {
+		index[c.ID()] = i
+	}
+	for _, seg := range r.segments {
+		for t := int(seg.Start / systime.TickDuration); t < int(seg.End/systime.TickDuration); t++ {
+			rows[index[seg.CPU]][t] = pad(seg.Job, width)
+		}
+	}
+	for i, c := range r.cpus {
+		if _, err := fmt.Fprintf(w, "%-5s|%s|\n", c.Header(), strings.Join(rows[i], "")); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "%-5s 0%*v\n", "", width*ticks+1, r.end)
+	return err
+}
+
+func pad(s string, width int) string {
+	return s + strings.Repeat(" ", width-len(s))
+}
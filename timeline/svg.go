@@ -0,0 +1,59 @@
+package timeline
+
+import (
+	"dat320/lab4/scheduler/system/systime"
+	"fmt"
+	"html"
+	"io"
+)
+
+const (
+	svgTickWidth = 12 // pixels per tick
+	svgRowHeight = 24
+	svgLabelSize = 48 // width of the CPU labels
+)
+
+// palette holds the fill colors of the jobs, picked by job ID.
+var palette = []string{
+	"#4e79a7", "#f28e2b", "#e15759", "#76b7b2", "#59a14f",
+	"#edc948", "#b07aa1", "#ff9da7", "#9c755f", "#bab0ac",
+}
+
+// WriteSVG writes the recording as an SVG image with one row per CPU.
+func (r *Recorder) WriteSVG(w io.Writer) error {
+	ew := &errWriter{w: w}
+	ticks := int(r.end / systime.TickDuration)
+	width := svgLabelSize + ticks*svgTickWidth
+	height := (len(r.cpus) + 1) * svgRowHeight
+	ew.printf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-family="monospace" font-size="12">`+"\n", width, height)
+	row := make(map[int]int, len(r.cpus))
+	for i, c := range r.cpus {
+		row[c.ID()] = i
+		ew.printf(`<text x="4" y="%d">%s</text>`+"\n", i*svgRowHeight+16, c.Header())
+	}
+	for _, seg := range r.segments {
+		x := svgLabelSize + int(seg.Start/systime.TickDuration)*svgTickWidth
+		y := row[seg.CPU] * svgRowHeight
+		segWidth := int((seg.End-seg.Start)/systime.TickDuration) * svgTickWidth
+		ew.printf(`<rect x="%d" y="%d" width="%d" height="%d" fill="%s" stroke="white"><title>%s %v-%v</title></rect>`+"\n",
+			x, y+2, segWidth, svgRowHeight-4, palette[seg.JobID%len(palette)], html.EscapeString(seg.Job), seg.Start, seg.End)
+		ew.printf(`<text x="%d" y="%d" fill="white">%s</text>`+"\n", x+2, y+16, html.EscapeString(seg.Job))
+	}
+	ew.printf(`<text x="%d" y="%d">0</text>`+"\n", svgLabelSize, len(r.cpus)*svgRowHeight+16)
+	ew.printf(`<text x="%d" y="%d" text-anchor="end">%v</text>`+"\n", width, len(r.cpus)*svgRowHeight+16, r.end)
+	ew.printf("</svg>\n")
+	return ew.err
+}
+
+// errWriter writes formatted output until a write fails,
+// and keeps the error of the failed write.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) printf(format string, args ...interface{}) {
+	if ew.err == nil {
+		_, ew.err = fmt.Fprintf(ew.w, format, args...)
+	}
+}
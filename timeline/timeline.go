@@ -0,0 +1,93 @@
+// Package timeline records which job runs on each CPU over time, and renders
+// the recording as an ASCII Gantt chart, an SVG image or a Chrome trace.
+//
+// A recorder is attached to a simulation with system.System.OnTick:
+//
+//	rec := timeline.New(cpus)
+//	sys := system.New(sched, schedule)
+//	sys.OnTick(rec.Record)
+package timeline
+
+import (
+	"dat320/lab4/scheduler/cpu"
+	"dat320/lab4/scheduler/job"
+	"dat320/lab4/scheduler/system/systime"
+	"time"
+)
+
+// Segment is a period of time during which a job ran uninterrupted on a CPU.
+type Segment struct {
+	CPU   int
+	JobID int
+	Job   string // name of the job
+	Start time.Duration
+	End   time.Duration
+}
+
+// Recorder records the jobs running on a set of CPUs.
+type Recorder struct {
+	cpus     []*cpu.CPU
+	open     []*Segment // segment in progress on each CPU; nil if idle
+	running  []*job.Job // job of the segment in progress on each CPU
+	segments []*Segment
+	end      time.Duration
+	last     time.Duration // time of the previous call to Record
+	recorded bool          // true once Record has been called
+}
+
+// New returns a recorder for the given CPUs.
+func New(cpus []*cpu.CPU) *Recorder {
+	return &Recorder{
+		cpus:    cpus,
+		open:    make([]*Segment, len(cpus)),
+		running: make([]*job.Job, len(cpus)),
+	}
+}
+
+// Record records the jobs that run on the CPUs during the tick at the given
+// system time; that is, the jobs assigned to the CPUs before the scheduler's
+// Tick runs them. The tick covers the time from now - systime.TickDuration to now.
+// If ticks were skipped since the previous call, as in a tickless simulation,
+// the jobs are recorded as running since the previous call. No job runs
+// before time zero.
+func (r *Recorder) Record(now time.Duration) {
+	start := now - systime.TickDuration
+	if r.recorded {
+		start = r.last
+	}
+	if start < 0 {
+		start = 0
+	}
+	r.last, r.recorded = now, true
+	for i, c := range r.cpus {
+		current := c.CurrentJob()
+		seg := r.open[i]
+		switch {
+		case current == nil:
+			r.open[i], r.running[i] = nil, nil
+		case seg != nil && r.running[i] == current:
+			seg.End = now
+		default:
+			seg = &Segment{CPU: c.ID(), JobID: current.ID(), Job: current.Name(), Start: start, End: now}
+			r.segments = append(r.segments, seg)
+			r.open[i], r.running[i] = seg, current
+		}
+	}
+	if now > r.end {
+		r.end = now
+	}
+}
+
+// Segments returns the recorded segments ordered by start time.
+func (r *Recorder) Segments() []Segment {
+	segments := make([]Segment, len(r.segments))
+	for i, seg := range r.segments {
+		segments[i] = *seg
+	}
+	return segments
+}
+
+// End returns the end of the recorded period.
+func (r *Recorder) End() time.Duration {
+	return r.end
+}
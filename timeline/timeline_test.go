@@ -0,0 +1,134 @@
+package timeline
+
+import (
+	"bytes"
+	"dat320/lab4/scheduler/cpu"
+	"dat320/lab4/scheduler/job"
+	"dat320/lab4/scheduler/rr"
+	"dat320/lab4/scheduler/system"
+	"dat320/lab4/scheduler/system/systime"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const ms = time.Millisecond
+
+func record(t *testing.T) *Recorder {
+	t.Helper()
+	jobs := job.NewFactory()
+	cpus := cpu.NewCPUs(2)
+	rec := New(cpus)
+	sys := system.New(rr.New(cpus, 2*ms), system.Schedule{
+		{Job: jobs.New(0, 3*ms), Arrival: 0},
+		{Job: jobs.New(0, 3*ms), Arrival: 0},
+		{Job: jobs.New(0, 2*ms), Arrival: 0},
+	})
+	sys.OnTick(rec.Record)
+	if _, err := sys.Run(); err != nil {
+		t.Fatal(err)
+	}
+	return rec
+}
+
+func TestSegments(t *testing.T) {
+	want := []Segment{
+		{CPU: 0, JobID: 1, Job: "A", Start: 0, End: 2 * ms},
+		{CPU: 1, JobID: 2, Job: "B", Start: 0, End: 2 * ms},
+		{CPU: 0, JobID: 3, Job: "C", Start: 2 * ms, End: 4 * ms},
+		{CPU: 1, JobID: 1, Job: "A", Start: 2 * ms, End: 3 * ms},
+		{CPU: 1, JobID: 2, Job: "B", Start: 3 * ms, End: 4 * ms},
+	}
+	if got := record(t).Segments(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Segments() = %+v, want %+v", got, want)
+	}
+}
+
+func TestWriteGantt(t *testing.T) {
+	var b bytes.Buffer
+	if err := record(t).WriteGantt(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := "CPU0 |AACC|\nCPU1 |BBAB|\n      0  4ms\n"
+	if got := b.String(); got != want {
+		t.Errorf("WriteGantt() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestWriteSVG(t *testing.T) {
+	var b bytes.Buffer
+	if err := record(t).WriteSVG(&b); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(b.String(), "<rect"); got != 5 {
+		t.Errorf("WriteSVG() has %d rectangles, want 5", got)
+	}
+}
+
+var errWrite = errors.New("write failed")
+
+// failingWriter fails every write, and counts the attempts.
+type failingWriter struct{ writes int }
+
+func (w *failingWriter) Write([]byte) (int, error) {
+	w.writes++
+	return 0, errWrite
+}
+
+func TestWriteSVGError(t *testing.T) {
+	w := &failingWriter{}
+	if err := record(t).WriteSVG(w); err != errWrite {
+		t.Errorf("WriteSVG() error = %v, want %v", err, errWrite)
+	}
+	if w.writes != 1 {
+		t.Errorf("WriteSVG() attempted %d writes, want 1", w.writes)
+	}
+}
+
+func TestRecordAtZero(t *testing.T) {
+	cpus := cpu.NewCPUs(1)
+	j := job.NewFactory().New(0, 2*ms)
+	j.Scheduled(&systime.ManualClock{})
+	cpus[0].Assign(j)
+	rec := New(cpus)
+	rec.Record(0)
+	want := []Segment{{CPU: 0, JobID: 1, Job: "A", Start: 0, End: 0}}
+	if got := rec.Segments(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Segments() = %+v, want %+v", got, want)
+	}
+}
+
+func TestRecordSameIDs(t *testing.T) {
+	clk := &systime.ManualClock{}
+	// jobs from different factories may have the same ID
+	a, b := job.NewFactory().New(0, 2*ms), job.NewFactory().New(0, 2*ms)
+	a.Scheduled(clk)
+	b.Scheduled(clk)
+	cpus := cpu.NewCPUs(1)
+	rec := New(cpus)
+	cpus[0].Assign(a)
+	rec.Record(1 * ms)
+	cpus[0].Assign(b)
+	rec.Record(2 * ms)
+	if got := len(rec.Segments()); got != 2 {
+		t.Errorf("Segments() has %d segments, want 2: %+v", got, rec.Segments())
+	}
+}
+
+func TestWriteTrace(t *testing.T) {
+	var b bytes.Buffer
+	if err := record(t).WriteTrace(&b); err != nil {
+		t.Fatal(err)
+	}
+	var got trace
+	if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := traceEvent{Name: "C", Phase: "X", Start: 2000, Dur: 2000, TID: 0}
+	if len(got.TraceEvents) != 5 || got.TraceEvents[2] != want {
+		t.Errorf("WriteTrace() events = %+v, want 5 events with %+v third", got.TraceEvents, want)
+	}
+}
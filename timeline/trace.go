@@ -0,0 +1,38 @@
+package timeline
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// traceEvent is a complete event in the Chrome trace event format,
+// with timestamps and durations in microseconds.
+type traceEvent struct {
+	Name  string `json:"name"`
+	Phase string `json:"ph"`
+	Start int64  `json:"ts"`
+	Dur   int64  `json:"dur"`
+	PID   int    `json:"pid"`
+	TID   int    `json:"tid"`
+}
+
+type trace struct {
+	TraceEvents     []traceEvent `json:"traceEvents"`
+	DisplayTimeUnit string       `json:"displayTimeUnit"`
+}
+
+// WriteTrace writes the recording in the Chrome trace event format, which can
+// be opened in chrome://tracing or Perfetto. Each CPU is shown as a thread.
+func (r *Recorder) WriteTrace(w io.Writer) error {
+	t := trace{TraceEvents: make([]traceEvent, len(r.segments)), DisplayTimeUnit: "ms"}
+	for i, seg := range r.segments {
+		t.TraceEvents[i] = traceEvent{
+			Name:  seg.Job,
+			Phase: "X",
+			Start: seg.Start.Microseconds(),
+			Dur:   (seg.End - seg.Start).Microseconds(),
+			TID:   seg.CPU,
+		}
+	}
+	return json.NewEncoder(w).Encode(t)
+}
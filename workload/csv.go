@@ -0,0 +1,69 @@
+package workload
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ReadCSV reads a workload in CSV format.
+func ReadCSV(r io.Reader) (Workload, error) {
+	var (
+		w      Workload
+		header []string
+	)
+	scanner := bufio.NewScanner(r)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, ",")
+		for i := range fields {
+			fields[i] = strings.ToLower(strings.TrimSpace(fields[i]))
+		}
+		if header == nil {
+			if err := checkHeader(fields); err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNum, err)
+			}
+			header = fields
+			continue
+		}
+		if len(fields) != len(header) {
+			return nil, fmt.Errorf("line %d: %w: got %d, want %d", lineNum, errColumnCount, len(fields), len(header))
+		}
+		var spec Spec
+		for i, value := range fields {
+			if err := spec.set(header[i], value); err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNum, err)
+			}
+		}
+		if err := spec.validate(); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		w = append(w, spec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// checkHeader returns an error if the header has unknown columns
+// or does not have the estimated column.
+func checkHeader(header []string) error {
+	var spec Spec
+	hasEstimated := false
+	for _, column := range header {
+		// the zero value is valid for every column
+		if err := spec.set(column, "0"); err != nil {
+			return err
+		}
+		hasEstimated = hasEstimated || column == "estimated"
+	}
+	if !hasEstimated {
+		return errMissingEstimated
+	}
+	return nil
+}
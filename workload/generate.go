@@ -0,0 +1,148 @@
+package workload
+
+import (
+	"dat320/lab4/scheduler/system/systime"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+var (
+	errNoBursts = errors.New("invalid argument: a burst length distribution is required")
+	errNoJobs   = errors.New("invalid argument: number of jobs must be greater than 0")
+)
+
+// Distribution is a probability distribution of durations.
+type Distribution interface {
+	// Draw returns a random duration drawn using the given source.
+	Draw(rng *rand.Rand) time.Duration
+}
+
+// Constant always draws the same duration.
+type Constant time.Duration
+
+// Draw returns the constant duration.
+func (c Constant) Draw(*rand.Rand) time.Duration {
+	return time.Duration(c)
+}
+
+// Uniform draws durations uniformly from [Min, Max).
+type Uniform struct {
+	Min, Max time.Duration
+}
+
+// Draw returns a uniformly distributed duration.
+func (u Uniform) Draw(rng *rand.Rand) time.Duration {
+	if u.Max <= u.Min {
+		return u.Min
+	}
+	return u.Min + time.Duration(rng.Int63n(int64(u.Max-u.Min)))
+}
+
+// Exponential draws exponentially distributed durations with the given mean.
+type Exponential struct {
+	Mean time.Duration
+}
+
+// Draw returns an exponentially distributed duration.
+func (e Exponential) Draw(rng *rand.Rand) time.Duration {
+	return time.Duration(rng.ExpFloat64() * float64(e.Mean))
+}
+
+// Poisson returns the distribution of the inter-arrival times of a Poisson
+// process with the given mean number of arrivals per second.
+// Poisson panics if the rate is not greater than 0.
+func Poisson(rate float64) Exponential {
+	if !(rate > 0) {
+		panic("workload: Poisson rate must be greater than 0")
+	}
+	return Exponential{Mean: time.Duration(float64(time.Second) / rate)}
+}
+
+// Pareto draws heavy-tailed durations from a Pareto distribution with
+// minimum Min and shape Alpha; the smaller Alpha, the heavier the tail.
+// The mean is Alpha*Min/(Alpha-1) for Alpha > 1.
+type Pareto struct {
+	Min   time.Duration
+	Alpha float64
+	Max   time.Duration // cap on drawn durations; zero means no cap
+}
+
+// Draw returns a Pareto distributed duration.
+// Draw panics if Min or Alpha is not greater than 0.
+func (p Pareto) Draw(rng *rand.Rand) time.Duration {
+	if p.Min <= 0 || !(p.Alpha > 0) {
+		panic("workload: Pareto Min and Alpha must be greater than 0")
+	}
+	u := 1 - rng.Float64() // in (0, 1]
+	d := float64(p.Min) * math.Pow(u, -1/p.Alpha)
+	if p.Max > 0 && d > float64(p.Max) {
+		return p.Max
+	}
+	return time.Duration(d)
+}
+
+// Bimodal draws from Long with probability PLong, and from Short otherwise;
+// for example, a mix of many short interactive jobs and a few long batch jobs.
+type Bimodal struct {
+	Short, Long Distribution
+	PLong       float64
+}
+
+// Draw returns a duration from either the short or the long distribution.
+func (b Bimodal) Draw(rng *rand.Rand) time.Duration {
+	if rng.Float64() < b.PLong {
+		return b.Long.Draw(rng)
+	}
+	return b.Short.Draw(rng)
+}
+
+// Generator generates synthetic workloads. The same generator and seed
+// always generate the same workload.
+type Generator struct {
+	Jobs     int          // number of jobs
+	Arrivals Distribution // inter-arrival times; nil if all jobs arrive at 0
+	Bursts   Distribution // estimated durations
+	Size     int          // working set size of every job
+	Tickets  int          // tickets of every job
+	Seed     int64
+}
+
+// Generate returns a workload of jobs with inter-arrival times and estimated
+// durations drawn from the generator's distributions. Durations are rounded
+// to whole ticks, and estimated durations are at least one tick. Generate
+// returns an error if an inter-arrival time drawn is negative.
+// Use Workload.Schedule to create the jobs.
+func (g Generator) Generate() (Workload, error) {
+	switch {
+	case g.Jobs <= 0:
+		return nil, errNoJobs
+	case g.Bursts == nil:
+		return nil, errNoBursts
+	}
+	rng := rand.New(rand.NewSource(g.Seed))
+	w := make(Workload, g.Jobs)
+	var arrival time.Duration
+	for i := range w {
+		if g.Arrivals != nil && i > 0 {
+			next := g.Arrivals.Draw(rng)
+			if next < 0 {
+				return nil, fmt.Errorf("job %d: inter-arrival time %v: %w", i, next, errNegativeArrival)
+			}
+			arrival += next
+		}
+		estimated := g.Bursts.Draw(rng).Round(systime.TickDuration)
+		if estimated < systime.TickDuration {
+			estimated = systime.TickDuration
+		}
+		w[i] = Spec{
+			Arrival:   arrival.Round(systime.TickDuration),
+			Estimated: estimated,
+			Size:      g.Size,
+			Tickets:   g.Tickets,
+		}
+	}
+	return w, nil
+}
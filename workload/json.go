@@ -0,0 +1,58 @@
+package workload
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// jsonDuration is a duration written either as a Go duration string
+// or as a plain number of ticks.
+type jsonDuration time.Duration
+
+func (d *jsonDuration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		// not a string; a number of ticks
+		s = string(b)
+	}
+	dur, err := parseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = jsonDuration(dur)
+	return nil
+}
+
+type jsonSpec struct {
+	Arrival   jsonDuration `json:"arrival"`
+	Estimated jsonDuration `json:"estimated"`
+	Size      int          `json:"size"`
+	Tickets   int          `json:"tickets"`
+	Priority  int          `json:"priority"`
+}
+
+// ReadJSON reads a workload in JSON format.
+func ReadJSON(r io.Reader) (Workload, error) {
+	var specs []jsonSpec
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&specs); err != nil {
+		return nil, err
+	}
+	w := make(Workload, len(specs))
+	for i, js := range specs {
+		w[i] = Spec{
+			Arrival:   time.Duration(js.Arrival),
+			Estimated: time.Duration(js.Estimated),
+			Size:      js.Size,
+			Tickets:   js.Tickets,
+			Priority:  js.Priority,
+		}
+		if err := w[i].validate(); err != nil {
+			return nil, fmt.Errorf("job %d: %w", i, err)
+		}
+	}
+	return w, nil
+}
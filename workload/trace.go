@@ -0,0 +1,193 @@
+package workload
+
+import (
+	"bufio"
+	"dat320/lab4/scheduler/system/systime"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ClockTicks is the number of clock ticks per second used for the times in
+// /proc/<pid>/stat, that is, sysconf(_SC_CLK_TCK), which is 100 on Linux.
+const ClockTicks = 100
+
+var (
+	errTraceFields = errors.New("want pid, arrival and CPU time fields")
+	errProcStat    = errors.New("malformed /proc/<pid>/stat line")
+	errNegative    = errors.New("times must not be negative")
+)
+
+// Record is the accounting record of a process in a trace.
+type Record struct {
+	PID     int
+	Start   time.Duration // when the process started
+	CPUTime time.Duration // user and system time used by the process
+}
+
+// Trace is a list of process accounting records.
+type Trace []Record
+
+// ReadTrace reads a trace with one process per line, given by its pid,
+// arrival time and CPU time, separated by commas or white space:
+//
+//	# pid  arrival  cputime
+//	4242   0.00     1.25
+//	4243   0.50     0.02
+//
+// As in process accounting records, times are in seconds; Go durations
+// such as 20ms are also accepted. Lines starting with # are comments,
+// and a first line starting with pid is taken as a header.
+func ReadTrace(r io.Reader) (Trace, error) {
+	var t Trace
+	scanner := bufio.NewScanner(r)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if len(t) == 0 && strings.HasPrefix(strings.ToLower(line), "pid") {
+			continue
+		}
+		fields := strings.FieldsFunc(line, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("line %d: %w: got %d fields", lineNum, errTraceFields, len(fields))
+		}
+		var (
+			rec Record
+			err error
+		)
+		if rec.PID, err = strconv.Atoi(fields[0]); err != nil {
+			return nil, fmt.Errorf("line %d: pid: %w", lineNum, err)
+		}
+		if rec.Start, err = parseSeconds(fields[1]); err != nil {
+			return nil, fmt.Errorf("line %d: arrival: %w", lineNum, err)
+		}
+		if rec.CPUTime, err = parseSeconds(fields[2]); err != nil {
+			return nil, fmt.Errorf("line %d: cputime: %w", lineNum, err)
+		}
+		if rec.Start < 0 || rec.CPUTime < 0 {
+			return nil, fmt.Errorf("line %d: %w", lineNum, errNegative)
+		}
+		t = append(t, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// parseSeconds parses a number of seconds, or a Go duration.
+func parseSeconds(s string) (time.Duration, error) {
+	if secs, err := strconv.ParseFloat(s, 64); err == nil {
+		return time.Duration(secs * float64(time.Second)), nil
+	}
+	return time.ParseDuration(s)
+}
+
+// ReadProcStat reads snapshots of /proc/<pid>/stat files, one per line, as
+// captured by, for example, repeatedly running cat /proc/[0-9]*/stat.
+// A process's start time is its starttime field, and its CPU time is the sum
+// of its utime and stime fields, all in clock ticks of 1/ClockTicks seconds.
+// Since CPU times only grow, the last snapshot of each process is used;
+// processes are identified by their pid and start time, as pids are reused.
+func ReadProcStat(r io.Reader) (Trace, error) {
+	type key struct {
+		pid   int
+		start time.Duration
+	}
+	var (
+		t     Trace
+		index = make(map[key]int)
+	)
+	scanner := bufio.NewScanner(r)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		rec, err := parseProcStat(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		k := key{rec.PID, rec.Start}
+		if i, ok := index[k]; ok {
+			t[i] = rec
+			continue
+		}
+		index[k] = len(t)
+		t = append(t, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// parseProcStat parses a /proc/<pid>/stat line. The second field is the
+// command name in parentheses, which may itself contain spaces and parentheses,
+// so the remaining fields are counted from the last closing parenthesis.
+// See proc(5) for the fields.
+func parseProcStat(line string) (Record, error) {
+	const (
+		utime     = 14
+		stime     = 15
+		starttime = 22
+		firstRest = 3 // number of the first field after the command name
+	)
+	open, end := strings.IndexByte(line, '('), strings.LastIndexByte(line, ')')
+	if open < 0 || end < open {
+		return Record{}, errProcStat
+	}
+	rest := strings.Fields(line[end+1:])
+	if len(rest) <= starttime-firstRest {
+		return Record{}, fmt.Errorf("%w: got %d fields", errProcStat, len(rest)+firstRest-1)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(line[:open]))
+	if err != nil {
+		return Record{}, fmt.Errorf("%w: pid: %v", errProcStat, err)
+	}
+	var ticks [3]int64
+	for i, field := range []int{utime, stime, starttime} {
+		if ticks[i], err = strconv.ParseInt(rest[field-firstRest], 10, 64); err != nil {
+			return Record{}, fmt.Errorf("%w: field %d: %v", errProcStat, field, err)
+		}
+	}
+	return Record{
+		PID:     pid,
+		Start:   clockTicks(ticks[2]),
+		CPUTime: clockTicks(ticks[0] + ticks[1]),
+	}, nil
+}
+
+// clockTicks returns the duration of n clock ticks.
+func clockTicks(n int64) time.Duration {
+	return time.Duration(n) * time.Second / ClockTicks
+}
+
+// Workload returns the trace as a workload ordered by start time, with one job
+// per process. Arrival times are relative to the first process, so that the
+// first job arrives at 0. Times are rounded to whole ticks, and every job
+// runs for at least one tick, so processes that used no measurable CPU time
+// still arrive in the simulation.
+func (t Trace) Workload() Workload {
+	records := make(Trace, len(t))
+	copy(records, t)
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Start < records[j].Start })
+	w := make(Workload, len(records))
+	for i, rec := range records {
+		estimated := rec.CPUTime.Round(systime.TickDuration)
+		if estimated < systime.TickDuration {
+			estimated = systime.TickDuration
+		}
+		w[i] = Spec{
+			Arrival:   (rec.Start - records[0].Start).Round(systime.TickDuration),
+			Estimated: estimated,
+		}
+	}
+	return w
+}
@@ -0,0 +1,150 @@
+// Package workload reads workload descriptions for scheduler simulations.
+//
+// A workload is a list of jobs, each with an arrival time, an estimated
+// duration, a working set size, a number of tickets and a priority.
+// Workloads can be written as CSV or JSON. In CSV, the first non-comment
+// line is a header naming the columns, in any order; lines starting
+// with # are comments:
+//
+//	# arrival, estimated, size, tickets, priority
+//	arrival,estimated,size,tickets,priority
+//	0ms,    30ms,     1,    100,     0
+//	10ms,   20ms,     1,    50,      1
+//
+// In JSON, the workload is an array of objects with the same keys:
+//
+//	[{"arrival": "0ms", "estimated": "30ms", "tickets": 100}]
+//
+// Durations are Go durations such as 10ms; a plain number is a number of ticks.
+// Omitted columns default to zero.
+//
+// Workloads can also be replayed from traces of real processes, given as
+// accounting records or /proc/<pid>/stat snapshots; see Trace.
+// Synthetic workloads can be generated from statistical distributions
+// of arrival times and durations with a Generator.
+package workload
+
+import (
+	"dat320/lab4/scheduler/job"
+	"dat320/lab4/scheduler/system"
+	"dat320/lab4/scheduler/system/systime"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	errNoEstimate       = errors.New("estimated duration must be greater than 0")
+	errNegativeArrival  = errors.New("arrival time must not be negative")
+	errNegativeSize     = errors.New("size and tickets must not be negative")
+	errUnknownColumn    = errors.New("unknown column")
+	errMissingEstimated = errors.New("missing estimated column")
+	errColumnCount      = errors.New("wrong number of columns")
+)
+
+// Spec describes a single job of a workload.
+type Spec struct {
+	Arrival   time.Duration
+	Estimated time.Duration
+	Size      int
+	Tickets   int
+	Priority  int
+}
+
+func (s Spec) validate() error {
+	switch {
+	case s.Estimated <= 0:
+		return errNoEstimate
+	case s.Arrival < 0:
+		return errNegativeArrival
+	case s.Size < 0 || s.Tickets < 0:
+		return errNegativeSize
+	}
+	return nil
+}
+
+// Workload is a list of job specifications.
+type Workload []Spec
+
+// Schedule creates a new job for each spec in the workload, and returns
+// the jobs ordered by arrival time; jobs arriving at the same time keep
+// their order in the workload. Each call creates new jobs with their own
+// job.Factory, so the same workload can be run through several schedulers,
+// also in parallel, and its jobs are named A, B, C, and so on, every time.
+func (w Workload) Schedule() system.Schedule {
+	specs := make(Workload, len(w))
+	copy(specs, w)
+	sort.SliceStable(specs, func(i, j int) bool { return specs[i].Arrival < specs[j].Arrival })
+	schedule := make(system.Schedule, len(specs))
+	factory := job.NewFactory()
+	for i, spec := range specs {
+		j := factory.New(spec.Size, spec.Estimated)
+		j.Tickets = spec.Tickets
+		j.Priority = spec.Priority
+		schedule[i] = &system.Entry{Job: j, Arrival: spec.Arrival}
+	}
+	return schedule
+}
+
+// Load reads a workload from the named file. Files ending in .json are
+// parsed as JSON, files ending in .trace as a trace (see ReadTrace), files
+// ending in .stat as /proc/<pid>/stat snapshots (see ReadProcStat); all other
+// files are parsed as CSV.
+func Load(path string) (Workload, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var read func(io.Reader) (Trace, error)
+	switch {
+	case strings.HasSuffix(path, ".json"):
+		return ReadJSON(f)
+	case strings.HasSuffix(path, ".trace"):
+		read = ReadTrace
+	case strings.HasSuffix(path, ".stat"):
+		read = ReadProcStat
+	default:
+		return ReadCSV(f)
+	}
+	t, err := read(f)
+	if err != nil {
+		return nil, err
+	}
+	return t.Workload(), nil
+}
+
+// parseDuration parses a Go duration, or a plain number of ticks.
+func parseDuration(s string) (time.Duration, error) {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Duration(n) * systime.TickDuration, nil
+	}
+	return time.ParseDuration(s)
+}
+
+// set assigns the value to the named field of the spec.
+func (s *Spec) set(field, value string) (err error) {
+	switch field {
+	case "arrival":
+		s.Arrival, err = parseDuration(value)
+	case "estimated":
+		s.Estimated, err = parseDuration(value)
+	case "size":
+		s.Size, err = strconv.Atoi(value)
+	case "tickets":
+		s.Tickets, err = strconv.Atoi(value)
+	case "priority":
+		s.Priority, err = strconv.Atoi(value)
+	default:
+		return fmt.Errorf("%q: %w", field, errUnknownColumn)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", field, err)
+	}
+	return nil
+}
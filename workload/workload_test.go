@@ -0,0 +1,229 @@
+package workload
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const ms = time.Millisecond
+
+const csvWorkload = `
+# a mixed workload
+priority, estimated, arrival, tickets
+1,        30ms,      10ms,    100
+0,        5,         0,       50
+`
+
+const jsonWorkload = `[
+	{"arrival": "10ms", "estimated": "30ms", "tickets": 100, "priority": 1},
+	{"arrival": 0, "estimated": 5, "tickets": 50}
+]`
+
+var wantWorkload = Workload{
+	{Arrival: 10 * ms, Estimated: 30 * ms, Tickets: 100, Priority: 1},
+	{Arrival: 0, Estimated: 5 * ms, Tickets: 50},
+}
+
+func TestRead(t *testing.T) {
+	readers := map[string]func(string) (Workload, error){
+		"CSV":  func(s string) (Workload, error) { return ReadCSV(strings.NewReader(s)) },
+		"JSON": func(s string) (Workload, error) { return ReadJSON(strings.NewReader(s)) },
+	}
+	inputs := map[string]string{"CSV": csvWorkload, "JSON": jsonWorkload}
+	for name, read := range readers {
+		got, err := read(inputs[name])
+		if err != nil {
+			t.Errorf("Read%s() error = %v", name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, wantWorkload) {
+			t.Errorf("Read%s() = %+v, want %+v", name, got, wantWorkload)
+		}
+	}
+}
+
+func TestReadCSVErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  error
+	}{
+		{"arrival,estimated,color\n0,1,red", errUnknownColumn},
+		{"arrival,size\n0,1", errMissingEstimated},
+		{"arrival,estimated\n0", errColumnCount},
+		{"arrival,estimated\n0,0", errNoEstimate},
+		{"arrival,estimated\n-1ms,1", errNegativeArrival},
+		{"estimated,tickets\n1,-5", errNegativeSize},
+	}
+	for _, test := range tests {
+		if _, err := ReadCSV(strings.NewReader(test.input)); !errors.Is(err, test.want) {
+			t.Errorf("ReadCSV(%q) error = %v, want %v", test.input, err, test.want)
+		}
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	schedule := wantWorkload.Schedule()
+	if len(schedule) != len(wantWorkload) {
+		t.Fatalf("Schedule() has %d entries, want %d", len(schedule), len(wantWorkload))
+	}
+	// entries are ordered by arrival time
+	first, second := schedule[0], schedule[1]
+	if first.Arrival != 0 || first.Job.Remaining() != 5*ms || first.Job.Tickets != 50 {
+		t.Errorf("Schedule()[0] = %v at %v, want job with 5ms and 50 tickets at 0s", first.Job, first.Arrival)
+	}
+	if second.Arrival != 10*ms || second.Job.Priority != 1 {
+		t.Errorf("Schedule()[1] = %v at %v, want job with priority 1 at 10ms", second.Job, second.Arrival)
+	}
+	// each call creates new jobs
+	if again := wantWorkload.Schedule(); again[0].Job == first.Job {
+		t.Error("Schedule() returned the same job twice, want new jobs")
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	g := Generator{
+		Jobs:     2000,
+		Arrivals: Poisson(100),
+		Bursts:   Exponential{Mean: 20 * ms},
+		Tickets:  100,
+		Seed:     1,
+	}
+	w, err := g.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, _ := g.Generate()
+	if !reflect.DeepEqual(w, again) {
+		t.Errorf("Generate() with the same seed generated different workloads")
+	}
+	var total time.Duration
+	for i, spec := range w {
+		if spec.Estimated < ms || spec.Estimated%ms != 0 || spec.Arrival%ms != 0 {
+			t.Fatalf("Generate()[%d] = %+v, want whole ticks of at least 1ms", i, spec)
+		}
+		if i > 0 && spec.Arrival < w[i-1].Arrival {
+			t.Fatalf("Generate()[%d] arrives before the previous job", i)
+		}
+		total += spec.Estimated
+	}
+	// 100 arrivals per second, so about 10ms between arrivals
+	if gap := w[len(w)-1].Arrival / time.Duration(len(w)-1); gap < 9*ms || gap > 11*ms {
+		t.Errorf("mean inter-arrival time = %v, want about 10ms", gap)
+	}
+	if mean := total / time.Duration(len(w)); mean < 18*ms || mean > 22*ms {
+		t.Errorf("mean estimated duration = %v, want about 20ms", mean)
+	}
+
+	if _, err := (Generator{Jobs: 1}).Generate(); !errors.Is(err, errNoBursts) {
+		t.Errorf("Generate() without bursts = %v, want %v", err, errNoBursts)
+	}
+	negative := Generator{Jobs: 2, Arrivals: Constant(-ms), Bursts: Constant(ms)}
+	if _, err := negative.Generate(); !errors.Is(err, errNegativeArrival) {
+		t.Errorf("Generate() with negative inter-arrival times = %v, want %v", err, errNegativeArrival)
+	}
+	for _, rate := range []float64{0, -100} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Poisson(%v) did not panic", rate)
+				}
+			}()
+			Poisson(rate)
+		}()
+	}
+	for _, p := range []Pareto{{Min: ms, Alpha: 0}, {Min: ms, Alpha: -1}, {Min: 0, Alpha: 1.5}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%+v.Draw() did not panic", p)
+				}
+			}()
+			p.Draw(rand.New(rand.NewSource(1)))
+		}()
+	}
+}
+
+func TestDistributions(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	pareto := Pareto{Min: 2 * ms, Alpha: 1.5, Max: time.Second}
+	bimodal := Bimodal{Short: Constant(1 * ms), Long: Constant(100 * ms), PLong: 0.1}
+	var long int
+	const n = 10000
+	for i := 0; i < n; i++ {
+		if d := pareto.Draw(rng); d < 2*ms || d > time.Second {
+			t.Fatalf("Pareto.Draw() = %v, want between 2ms and 1s", d)
+		}
+		if d := (Uniform{Min: 1 * ms, Max: 3 * ms}).Draw(rng); d < 1*ms || d >= 3*ms {
+			t.Fatalf("Uniform.Draw() = %v, want in [1ms, 3ms)", d)
+		}
+		if bimodal.Draw(rng) == 100*ms {
+			long++
+		}
+	}
+	if frac := float64(long) / n; frac < 0.08 || frac > 0.12 {
+		t.Errorf("Bimodal.Draw() drew the long mode %.3f of the time, want about 0.1", frac)
+	}
+}
+
+func TestReadTrace(t *testing.T) {
+	const trace = `
+# captured with lastcomm
+pid, arrival, cputime
+4242, 10.5,   0.25
+4243, 10.0,   20ms
+4244  12      0
+`
+	tr, err := ReadTrace(strings.NewReader(trace))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Trace{
+		{PID: 4242, Start: 10500 * ms, CPUTime: 250 * ms},
+		{PID: 4243, Start: 10 * time.Second, CPUTime: 20 * ms},
+		{PID: 4244, Start: 12 * time.Second, CPUTime: 0},
+	}
+	if !reflect.DeepEqual(tr, want) {
+		t.Errorf("ReadTrace() = %+v, want %+v", tr, want)
+	}
+	wantWorkload := Workload{
+		{Arrival: 0, Estimated: 20 * ms},
+		{Arrival: 500 * ms, Estimated: 250 * ms},
+		{Arrival: 2 * time.Second, Estimated: 1 * ms},
+	}
+	if got := tr.Workload(); !reflect.DeepEqual(got, wantWorkload) {
+		t.Errorf("Workload() = %+v, want %+v", got, wantWorkload)
+	}
+
+	for _, bad := range []string{"1 2", "x 1 1", "1 1 -1", "1 soon 1"} {
+		if _, err := ReadTrace(strings.NewReader(bad)); err == nil {
+			t.Errorf("ReadTrace(%q): got nil error", bad)
+		}
+	}
+}
+
+func TestReadProcStat(t *testing.T) {
+	const stat = `1234 (my (odd) cmd) S 1 1234 1234 0 -1 4194560 100 0 0 0 150 25 0 0 20 0 1 0 5000 12345678 300
+1300 (sh) R 1 1300 1300 0 -1 4194560 10 0 0 0 1 1 0 0 20 0 1 0 5100 1234 30
+1234 (my (odd) cmd) S 1 1234 1234 0 -1 4194560 100 0 0 0 180 30 0 0 20 0 1 0 5000 12345678 300
+`
+	tr, err := ReadProcStat(strings.NewReader(stat))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Trace{
+		{PID: 1234, Start: 50 * time.Second, CPUTime: 2100 * ms},
+		{PID: 1300, Start: 51 * time.Second, CPUTime: 20 * ms},
+	}
+	if !reflect.DeepEqual(tr, want) {
+		t.Errorf("ReadProcStat() = %+v, want %+v", tr, want)
+	}
+	for _, bad := range []string{"1234 sh S 1", "1234 (sh) S 1 2 3", "x (sh) S 1 1234 1234 0 -1 4194560 100 0 0 0 150 25 0 0 20 0 1 0 5000"} {
+		if _, err := ReadProcStat(strings.NewReader(bad)); !errors.Is(err, errProcStat) {
+			t.Errorf("ReadProcStat(%q) = %v, want %v", bad, err, errProcStat)
+		}
+	}
+}